@@ -36,7 +36,6 @@ import (
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
 	"github.com/kcp-dev/kcp/pkg/conversion"
-	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
 )
 
 const (
@@ -56,13 +55,16 @@ type apiConversionAdmission struct {
 	*admission.Handler
 
 	getAPIResourceSchema func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
+
+	boundCRDsClusterName logicalcluster.Name
 }
 
 // Ensure that the required admission interfaces are implemented.
 var (
-	_ admission.ValidationInterface     = (*apiConversionAdmission)(nil)
-	_ admission.InitializationValidator = (*apiConversionAdmission)(nil)
-	_ initializers.WantsKcpInformers    = (*apiConversionAdmission)(nil)
+	_ admission.ValidationInterface          = (*apiConversionAdmission)(nil)
+	_ admission.InitializationValidator      = (*apiConversionAdmission)(nil)
+	_ initializers.WantsKcpInformers         = (*apiConversionAdmission)(nil)
+	_ initializers.WantsBoundCRDsClusterName = (*apiConversionAdmission)(nil)
 )
 
 // Validate ensures all the conversion rules specified in an APIConversion are correct.
@@ -76,7 +78,7 @@ func (o *apiConversionAdmission) Validate(ctx context.Context, a admission.Attri
 		return admission.NewForbidden(a, fmt.Errorf("error determining workspace: %w", err))
 	}
 
-	if cluster.Name == apibinding.SystemBoundCRDsClusterName {
+	if cluster.Name == o.boundCRDsClusterName {
 		// TODO(ncdc): do we also want to validate these conversions? They've already been validated once (in their
 		// original logical cluster).
 		return nil
@@ -129,10 +131,17 @@ func (o *apiConversionAdmission) ValidateInitialization() error {
 	if o.getAPIResourceSchema == nil {
 		return fmt.Errorf("getAPIResourceSchema is unset")
 	}
+	if o.boundCRDsClusterName.Empty() {
+		return fmt.Errorf("boundCRDsClusterName is unset")
+	}
 
 	return nil
 }
 
+func (o *apiConversionAdmission) SetBoundCRDsClusterName(clusterName logicalcluster.Name) {
+	o.boundCRDsClusterName = clusterName
+}
+
 func (o *apiConversionAdmission) SetKcpInformers(local, global kcpinformers.SharedInformerFactory) {
 	o.getAPIResourceSchema = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
 		apiResourceSchema, err := local.Apis().V1alpha1().APIResourceSchemas().Lister().Cluster(clusterName).Get(name)
@@ -20,12 +20,22 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
+
+	"github.com/kcp-dev/logicalcluster/v3"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apiserver/pkg/admission"
+	genericapirequest "k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/client-go/tools/cache"
 
+	"github.com/kcp-dev/kcp/pkg/admission/initializers"
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/client"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	"github.com/kcp-dev/kcp/pkg/indexers"
 )
 
 const (
@@ -36,24 +46,35 @@ func Register(plugins *admission.Plugins) {
 	plugins.Register(PluginName,
 		func(_ io.Reader) (admission.Interface, error) {
 			return &apiResourceSchemaValidation{
-				Handler: admission.NewHandler(admission.Create, admission.Update),
+				Handler: admission.NewHandler(admission.Create, admission.Update, admission.Delete),
 			}, nil
 		})
 }
 
 type apiResourceSchemaValidation struct {
 	*admission.Handler
+
+	listAPIExportsByAPIResourceSchema func(clusterName logicalcluster.Name, name string) ([]*apisv1alpha1.APIExport, error)
 }
 
 // Ensure that the required admission interfaces are implemented.
-var _ = admission.ValidationInterface(&apiResourceSchemaValidation{})
+var (
+	_ admission.ValidationInterface     = (*apiResourceSchemaValidation)(nil)
+	_ admission.InitializationValidator = (*apiResourceSchemaValidation)(nil)
+	_ initializers.WantsKcpInformers    = (*apiResourceSchemaValidation)(nil)
+)
 
-// Validate does validation of a APIResourceSchema for create and update.
+// Validate does validation of a APIResourceSchema for create and update, and rejects the deletion
+// of APIResourceSchemas that are still referenced by an APIExport.
 func (o *apiResourceSchemaValidation) Validate(ctx context.Context, a admission.Attributes, _ admission.ObjectInterfaces) (err error) {
 	if a.GetResource().GroupResource() != apisv1alpha1.Resource("apiresourceschemas") {
 		return nil
 	}
 
+	if a.GetOperation() == admission.Delete {
+		return o.validateDelete(ctx, a)
+	}
+
 	u, ok := a.GetObject().(*unstructured.Unstructured)
 	if !ok {
 		return fmt.Errorf("unexpected type %T", a.GetObject())
@@ -88,3 +109,54 @@ func (o *apiResourceSchemaValidation) Validate(ctx context.Context, a admission.
 
 	return nil
 }
+
+// validateDelete rejects the deletion of an APIResourceSchema referenced by an APIExport in the same
+// workspace, unless the schema is annotated with apis.kcp.io/force-delete=true.
+func (o *apiResourceSchemaValidation) validateDelete(ctx context.Context, a admission.Attributes) error {
+	if u, ok := a.GetOldObject().(*unstructured.Unstructured); ok && u.GetAnnotations()[apisv1alpha1.AnnotationForceDeleteKey] == "true" {
+		return nil
+	}
+
+	cluster, err := genericapirequest.ValidClusterFrom(ctx)
+	if err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("error determining workspace: %w", err))
+	}
+
+	exports, err := o.listAPIExportsByAPIResourceSchema(cluster.Name, a.GetName())
+	if err != nil {
+		return admission.NewForbidden(a, fmt.Errorf("error getting APIExports referencing APIResourceSchema %s: %w", a.GetName(), err))
+	}
+	if len(exports) == 0 {
+		return nil
+	}
+
+	names := sets.NewString()
+	for _, export := range exports {
+		names.Insert(export.Name)
+	}
+	return admission.NewForbidden(a, fmt.Errorf("APIResourceSchema is referenced by APIExport(s) %s; remove it from spec.latestResourceSchemas or set the %s=true annotation to force deletion",
+		strings.Join(names.List(), ", "), apisv1alpha1.AnnotationForceDeleteKey))
+}
+
+// ValidateInitialization ensures the required injected fields are set.
+func (o *apiResourceSchemaValidation) ValidateInitialization() error {
+	if o.listAPIExportsByAPIResourceSchema == nil {
+		return fmt.Errorf("listAPIExportsByAPIResourceSchema is unset")
+	}
+
+	return nil
+}
+
+func (o *apiResourceSchemaValidation) SetKcpInformers(local, global kcpinformers.SharedInformerFactory) {
+	apiExportIndexer := local.Apis().V1alpha1().APIExports().Informer().GetIndexer()
+	o.listAPIExportsByAPIResourceSchema = func(clusterName logicalcluster.Name, name string) ([]*apisv1alpha1.APIExport, error) {
+		key := client.ToClusterAwareKey(clusterName.Path(), name)
+		return indexers.ByIndex[*apisv1alpha1.APIExport](apiExportIndexer, indexers.APIExportByAPIResourceSchema, key)
+	}
+
+	o.SetReadyFunc(local.Apis().V1alpha1().APIExports().Informer().HasSynced)
+
+	indexers.AddIfNotPresentOrDie(apiExportIndexer, cache.Indexers{
+		indexers.APIExportByAPIResourceSchema: indexers.IndexAPIExportByAPIResourceSchema,
+	})
+}
@@ -21,6 +21,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/authentication/user"
@@ -185,6 +188,78 @@ spec:
 	}
 }
 
+func deleteAttr(s *apisv1alpha1.APIResourceSchema) admission.Attributes {
+	return admission.NewAttributesRecord(
+		nil,
+		helpers.ToUnstructuredOrDie(s),
+		apisv1alpha1.Kind("APIResourceSchema").WithVersion("v1alpha1"),
+		"",
+		s.Name,
+		apisv1alpha1.Resource("apiresourceschemas").WithVersion("v1alpha1"),
+		"",
+		admission.Delete,
+		&metav1.DeleteOptions{},
+		false,
+		&user.DefaultInfo{},
+	)
+}
+
+func TestValidateDelete(t *testing.T) {
+	newSchema := func(annotations map[string]string) *apisv1alpha1.APIResourceSchema {
+		return &apisv1alpha1.APIResourceSchema{
+			ObjectMeta: metav1.ObjectMeta{Name: "today.cowboys.wild.west", Annotations: annotations},
+		}
+	}
+	newExport := func(name string) *apisv1alpha1.APIExport {
+		return &apisv1alpha1.APIExport{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	tests := map[string]struct {
+		schema  *apisv1alpha1.APIResourceSchema
+		exports []*apisv1alpha1.APIExport
+		wantErr string
+	}{
+		"not referenced": {
+			schema: newSchema(nil),
+		},
+		"referenced": {
+			schema:  newSchema(nil),
+			exports: []*apisv1alpha1.APIExport{newExport("west"), newExport("wild")},
+			wantErr: "APIResourceSchema is referenced by APIExport(s) west, wild",
+		},
+		"referenced, force annotation not true": {
+			schema:  newSchema(map[string]string{apisv1alpha1.AnnotationForceDeleteKey: "yes"}),
+			exports: []*apisv1alpha1.APIExport{newExport("wild")},
+			wantErr: "APIResourceSchema is referenced by APIExport(s) wild",
+		},
+		"referenced, forced": {
+			schema:  newSchema(map[string]string{apisv1alpha1.AnnotationForceDeleteKey: "true"}),
+			exports: []*apisv1alpha1.APIExport{newExport("wild")},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			o := &apiResourceSchemaValidation{
+				Handler: admission.NewHandler(admission.Create, admission.Update, admission.Delete),
+				listAPIExportsByAPIResourceSchema: func(clusterName logicalcluster.Name, name string) ([]*apisv1alpha1.APIExport, error) {
+					require.Equal(t, logicalcluster.Name("root:org"), clusterName)
+					require.Equal(t, "today.cowboys.wild.west", name)
+					return tc.exports, nil
+				},
+			}
+			ctx := request.WithCluster(context.Background(), request.Cluster{Name: "root:org"})
+			err := o.Validate(ctx, deleteAttr(tc.schema), nil)
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tc.wantErr)
+			require.Contains(t, err.Error(), apisv1alpha1.AnnotationForceDeleteKey)
+		})
+	}
+}
+
 func unmarshalOrDie(yml string) *apisv1alpha1.APIResourceSchema {
 	s := apisv1alpha1.APIResourceSchema{}
 	if err := yaml.Unmarshal([]byte(strings.ReplaceAll(yml, "\t", "    ")), &s); err != nil {
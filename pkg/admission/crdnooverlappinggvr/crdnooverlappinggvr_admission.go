@@ -28,10 +28,10 @@ import (
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/endpoints/request"
 
+	"github.com/kcp-dev/kcp/pkg/admission/initializers"
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
 	apisv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
 )
 
 const (
@@ -51,21 +51,30 @@ type crdNoOverlappingGVRAdmission struct {
 	*admission.Handler
 
 	apiBindingClusterLister apisv1alpha1listers.APIBindingClusterLister
+	boundCRDsClusterName    logicalcluster.Name
 }
 
 // Ensure that the required admission interfaces are implemented.
 var _ = admission.ValidationInterface(&crdNoOverlappingGVRAdmission{})
 var _ = admission.InitializationValidator(&crdNoOverlappingGVRAdmission{})
+var _ = initializers.WantsBoundCRDsClusterName(&crdNoOverlappingGVRAdmission{})
 
 func (p *crdNoOverlappingGVRAdmission) SetKcpInformers(local, global kcpinformers.SharedInformerFactory) {
 	p.SetReadyFunc(local.Apis().V1alpha1().APIBindings().Informer().HasSynced)
 	p.apiBindingClusterLister = local.Apis().V1alpha1().APIBindings().Lister()
 }
 
+func (p *crdNoOverlappingGVRAdmission) SetBoundCRDsClusterName(clusterName logicalcluster.Name) {
+	p.boundCRDsClusterName = clusterName
+}
+
 func (p *crdNoOverlappingGVRAdmission) ValidateInitialization() error {
 	if p.apiBindingClusterLister == nil {
 		return fmt.Errorf(PluginName + " plugin needs an APIBindings lister")
 	}
+	if p.boundCRDsClusterName.Empty() {
+		return fmt.Errorf(PluginName + " plugin needs the logical cluster of bound CRDs")
+	}
 	return nil
 }
 
@@ -83,7 +92,7 @@ func (p *crdNoOverlappingGVRAdmission) Validate(ctx context.Context, a admission
 	}
 	clusterName := logicalcluster.Name(cluster.String()) // TODO(sttts): remove this cast once ClusterNameFrom returns a tenancy.Name
 	// ignore CRDs targeting system and non-root workspaces
-	if clusterName == apibinding.SystemBoundCRDsClusterName || clusterName == "system:admin" {
+	if clusterName == p.boundCRDsClusterName || clusterName == "system:admin" {
 		return nil
 	}
 
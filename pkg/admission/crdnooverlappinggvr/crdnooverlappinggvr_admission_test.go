@@ -93,7 +93,7 @@ func TestValidate(t *testing.T) {
 				}
 			}
 
-			a := &crdNoOverlappingGVRAdmission{Handler: admission.NewHandler(admission.Create, admission.Update), apiBindingClusterLister: apisv1alpha1listers.NewAPIBindingClusterLister(indexer)}
+			a := &crdNoOverlappingGVRAdmission{Handler: admission.NewHandler(admission.Create, admission.Update), apiBindingClusterLister: apisv1alpha1listers.NewAPIBindingClusterLister(indexer), boundCRDsClusterName: "system:bound-crds"}
 			ctx := request.WithCluster(context.Background(), request.Cluster{Name: scenario.clusterName})
 			if err := a.Validate(ctx, scenario.attr, nil); (err != nil) != scenario.wantErr {
 				t.Fatalf("Validate() error = %v, wantErr %v", err, scenario.wantErr)
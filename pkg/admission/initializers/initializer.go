@@ -18,6 +18,7 @@ package initializers
 
 import (
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v3"
 
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/admission/initializer"
@@ -143,3 +144,21 @@ func (i *serverShutdownChannelInitializer) Initialize(plugin admission.Interface
 		wants.SetServerShutdownChannel(i.ch)
 	}
 }
+
+// NewBoundCRDsClusterNameInitializer returns an admission plugin initializer that injects the logical cluster
+// the CRDs of APIBindings are created in into admission plugins.
+func NewBoundCRDsClusterNameInitializer(clusterName logicalcluster.Name) *boundCRDsClusterNameInitializer {
+	return &boundCRDsClusterNameInitializer{
+		clusterName: clusterName,
+	}
+}
+
+type boundCRDsClusterNameInitializer struct {
+	clusterName logicalcluster.Name
+}
+
+func (i *boundCRDsClusterNameInitializer) Initialize(plugin admission.Interface) {
+	if wants, ok := plugin.(WantsBoundCRDsClusterName); ok {
+		wants.SetBoundCRDsClusterName(i.clusterName)
+	}
+}
@@ -18,6 +18,7 @@ package initializers
 
 import (
 	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v3"
 
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
 	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
@@ -53,3 +54,9 @@ type WantsDeepSARClient interface {
 type WantsServerShutdownChannel interface {
 	SetServerShutdownChannel(<-chan struct{})
 }
+
+// WantsBoundCRDsClusterName interface should be implemented by admission plugins
+// that want to know the logical cluster the CRDs of APIBindings are created in.
+type WantsBoundCRDsClusterName interface {
+	SetBoundCRDsClusterName(logicalcluster.Name)
+}
@@ -29,8 +29,8 @@ import (
 	"k8s.io/apiserver/pkg/admission"
 	"k8s.io/apiserver/pkg/endpoints/request"
 
+	"github.com/kcp-dev/kcp/pkg/admission/initializers"
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
 )
 
 const (
@@ -48,10 +48,25 @@ func Register(plugins *admission.Plugins) {
 
 type reservedCRDAnnotations struct {
 	*admission.Handler
+
+	boundCRDsClusterName logicalcluster.Name
 }
 
 // Ensure that the required admission interfaces are implemented.
 var _ = admission.ValidationInterface(&reservedCRDAnnotations{})
+var _ = admission.InitializationValidator(&reservedCRDAnnotations{})
+var _ = initializers.WantsBoundCRDsClusterName(&reservedCRDAnnotations{})
+
+func (o *reservedCRDAnnotations) SetBoundCRDsClusterName(clusterName logicalcluster.Name) {
+	o.boundCRDsClusterName = clusterName
+}
+
+func (o *reservedCRDAnnotations) ValidateInitialization() error {
+	if o.boundCRDsClusterName.Empty() {
+		return fmt.Errorf(PluginName + " plugin needs the logical cluster of bound CRDs")
+	}
+	return nil
+}
 
 // Validate ensures that
 // - if the bound annotation exists that it is in the in the Shadow Workspace.
@@ -73,7 +88,7 @@ func (o *reservedCRDAnnotations) Validate(ctx context.Context, a admission.Attri
 		return fmt.Errorf("failed to retrieve cluster from context: %w", err)
 	}
 	clusterName := logicalcluster.Name(cluster.String()) // TODO(sttts): remove when ClusterFromfrom returns a tenancy.Name
-	if clusterName == apibinding.SystemBoundCRDsClusterName {
+	if clusterName == o.boundCRDsClusterName {
 		return nil
 	}
 
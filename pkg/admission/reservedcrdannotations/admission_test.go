@@ -212,7 +212,8 @@ func TestValidate(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			o := &reservedCRDAnnotations{
-				Handler: admission.NewHandler(admission.Create, admission.Update),
+				Handler:              admission.NewHandler(admission.Create, admission.Update),
+				boundCRDsClusterName: "system:bound-crds",
 			}
 			var ctx context.Context
 
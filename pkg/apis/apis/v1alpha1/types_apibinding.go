@@ -79,6 +79,23 @@ type APIBindingSpec struct {
 	//
 	// +optional
 	PermissionClaims []AcceptablePermissionClaim `json:"permissionClaims,omitempty"`
+
+	// exportGeneration pins the binding to the given metadata.generation of the referenced APIExport.
+	// If set, the APIs are not bound (or re-bound) while the APIExport has a different generation,
+	// and the APIExportPinned condition is false. Unlike the reference, it can be changed to roll forward.
+	// The generation is used because, unlike the resourceVersion, it is the same on every shard and in
+	// the cache server, and it does not change on status updates.
+	//
+	// +optional
+	ExportGeneration int64 `json:"exportGeneration,omitempty"`
+
+	// resourceSelector restricts the APIs that are bound to the given resources of the referenced
+	// APIExport. All APIs of the APIExport are bound if it is empty. Selecting a resource that the
+	// APIExport does not export fails the binding. Deselecting a resource that is already bound
+	// does not unbind it.
+	//
+	// +optional
+	ResourceSelector []GroupResource `json:"resourceSelector,omitempty"`
 }
 
 // AcceptablePermissionClaim is a PermissionClaim that records if the user accepts or rejects it.
@@ -171,6 +188,85 @@ type APIBindingStatus struct {
 	// the binding to grant.
 	// +optional
 	ExportPermissionClaims []PermissionClaim `json:"exportPermissionClaims,omitempty"`
+
+	// acceptedPermissionClaims records the permission claims of the APIExport that are accepted
+	// in spec.permissionClaims. The APIBinding is not bound until every claim of the APIExport
+	// is either accepted or rejected.
+	//
+	// +optional
+	AcceptedPermissionClaims []PermissionClaim `json:"acceptedPermissionClaims,omitempty"`
+
+	// apiExportGeneration is the generation of the APIExport the binding was last successfully
+	// bound to. Compare it with the APIExport to see whether the binding is behind. Unlike the
+	// resourceVersion, the generation is the same on every shard the APIExport is replicated to.
+	//
+	// +optional
+	APIExportGeneration int64 `json:"apiExportGeneration,omitempty"`
+
+	// schemaBindings records the binding state of each APIResourceSchema of the bound APIExport,
+	// including the ones that are not bound (yet).
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=group
+	// +listMapKey=resource
+	SchemaBindings []SchemaBindingStatus `json:"schemaBindings,omitempty"`
+
+	// observedGeneration is the generation of the APIBinding spec the controller last completed
+	// binding for. The status is stale if it is less than metadata.generation.
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// SchemaBindingState is the binding state of an APIResourceSchema.
+type SchemaBindingState string
+
+const (
+	// SchemaBindingStateEstablished means the bound CRD of the schema is established and the API is served.
+	SchemaBindingStateEstablished SchemaBindingState = "Established"
+	// SchemaBindingStatePending means the bound CRD of the schema is being created or is not established yet.
+	SchemaBindingStatePending SchemaBindingState = "Pending"
+	// SchemaBindingStateConflict means the schema cannot be bound because it conflicts with another API.
+	SchemaBindingStateConflict SchemaBindingState = "Conflict"
+	// SchemaBindingStateSkipped means the schema is not bound because spec.resourceSelector does not select it.
+	SchemaBindingStateSkipped SchemaBindingState = "Skipped"
+)
+
+// SchemaBindingStatus describes the binding state of a single APIResourceSchema of the bound APIExport.
+type SchemaBindingStatus struct {
+	// group is the group of the API. Empty string for the core API group.
+	//
+	// +required
+	Group string `json:"group"`
+
+	// resource is the resource of the API.
+	//
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Resource string `json:"resource"`
+
+	// schema is the name of the APIResourceSchema.
+	//
+	// +required
+	// +kubebuilder:validation:MinLength=1
+	Schema string `json:"schema"`
+
+	// crdName is the name of the bound CRD for the schema.
+	//
+	// +optional
+	CRDName string `json:"crdName,omitempty"`
+
+	// state is the binding state of the schema.
+	//
+	// +required
+	// +kubebuilder:validation:Enum=Established;Pending;Conflict;Skipped
+	State SchemaBindingState `json:"state"`
+
+	// message is a human readable message with details about the state.
+	//
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 // These are valid conditions of APIBinding.
@@ -183,6 +279,9 @@ const (
 	APIExportInvalidReferenceReason = "APIExportInvalidReference"
 	// APIExportNotFoundReason is a reason for the APIExportValid condition that the referenced APIExport is not found.
 	APIExportNotFoundReason = "APIExportNotFound"
+	// APIExportIdentityMismatchReason is a reason for the APIExportValid condition that the identity of the referenced
+	// APIExport differs from the identity the APIs were bound with, e.g. because the APIExport was re-created.
+	APIExportIdentityMismatchReason = "IdentityMismatch"
 
 	// APIResourceSchemaInvalidReason is a reason for the InitialBindingCompleted and BindingUpToDate conditions when one of generated CRD is invalid.
 	APIResourceSchemaInvalidReason = "APIResourceSchemaInvalid"
@@ -197,6 +296,11 @@ const (
 	// WaitingForEstablishedReason is a reason for the InitialBindingCompleted condition that the bound CRDs are not ready.
 	WaitingForEstablishedReason = "WaitingForEstablished"
 
+	// CRDNotEstablishedReason is a reason for the InitialBindingCompleted and BindingUpToDate conditions that
+	// a bound CRD did not become established in time. The message carries the reason reported by the CRD,
+	// e.g. a non-structural schema.
+	CRDNotEstablishedReason = "CRDNotEstablished"
+
 	// BindingUpToDate is a condition for APIBinding that indicates that the APIs currently bound are up-to-date with
 	// the binding's desired export.
 	BindingUpToDate conditionsv1alpha1.ConditionType = "BindingUpToDate"
@@ -205,6 +309,34 @@ const (
 	// has a naming conflict with other APIs.
 	NamingConflictsReason = "NamingConflicts"
 
+	// ResourceNotExportedReason is a reason for the InitialBindingCompleted and BindingUpToDate conditions that
+	// spec.resourceSelector selects a resource the APIExport does not export.
+	ResourceNotExportedReason = "ResourceNotExported"
+
+	// BoundCRDDriftedReason is a reason for the BindingUpToDate condition that the configuration of a bound CRD
+	// (e.g. its subresources) drifted from its APIResourceSchema and is being repaired.
+	BoundCRDDriftedReason = "BoundCRDDrifted"
+
+	// BoundResourcesExported is a condition for APIBinding that indicates whether every bound resource is still
+	// exported by the APIExport. It is only set if a bound resource was removed from the APIExport.
+	BoundResourcesExported conditionsv1alpha1.ConditionType = "BoundResourcesExported"
+
+	// SchemaRemovedReason is a reason for the BoundResourcesExported condition that the APIExport no longer exports
+	// a bound resource. The resource is still served from its bound CRD.
+	SchemaRemovedReason = "SchemaRemoved"
+
+	// APIExportPinned is a condition for APIBinding that indicates whether the referenced APIExport has the
+	// generation the APIBinding is pinned to via spec.exportGeneration.
+	APIExportPinned conditionsv1alpha1.ConditionType = "APIExportPinned"
+
+	// APIExportPinOutdatedReason is a reason for the APIExportPinned condition that the APIExport has a different
+	// generation than the APIBinding is pinned to.
+	APIExportPinOutdatedReason = "PinOutdated"
+
+	// DryRunReason is a reason for the BindingUpToDate and InitialBindingCompleted conditions that the APIBinding
+	// is in dry-run mode and bound CRDs have not been created.
+	DryRunReason = "DryRun"
+
 	// BindingResourceDeleteSuccess is a condition for APIBinding that indicates the resources relating this binding are deleted
 	// successfully when the APIBinding is deleting
 	BindingResourceDeleteSuccess conditionsv1alpha1.ConditionType = "BindingResourceDeleteSuccess"
@@ -219,8 +351,20 @@ const (
 	// PermissionClaimsApplied is a condition for APIBinding that indicates that all the accepted permission claims
 	// have been applied.
 	PermissionClaimsApplied conditionsv1alpha1.ConditionType = "PermissionClaimsApplied"
+
+	// PermissionClaimsAccepted is a condition for APIBinding that indicates that every permission claim of the
+	// APIExport has been accepted or rejected in spec.permissionClaims. It is only set if the APIExport has claims.
+	PermissionClaimsAccepted conditionsv1alpha1.ConditionType = "PermissionClaimsAccepted"
+
+	// ClaimsNotAcceptedReason is a reason for the PermissionClaimsAccepted condition that at least one permission
+	// claim of the APIExport is neither accepted nor rejected. The APIBinding is not bound until it is.
+	ClaimsNotAcceptedReason = "ClaimsNotAccepted"
 )
 
+// AnnotationDryRunKey is the annotation key on an APIBinding that, when set to "true", makes the APIBinding
+// controller compute the bound CRDs and report them in status without creating them.
+const AnnotationDryRunKey = "apis.kcp.io/dry-run"
+
 // These are annotations for bound CRDs
 const (
 	// AnnotationBoundCRDKey is the annotation key that indicates a CRD is for an APIExport (a "bound CRD").
@@ -235,6 +379,9 @@ const (
 	// for the request. This data is synthetic; it is not stored in etcd and instead is only applied when retrieving
 	// CRs for the CRD.
 	AnnotationAPIIdentityKey = "apis.kcp.io/identity"
+	// AnnotationBoundCRDCreatedAtKey is the annotation key for a bound CRD holding the RFC3339 time, with
+	// nanoseconds, the APIBinding controller created it at.
+	AnnotationBoundCRDCreatedAtKey = "apis.kcp.io/bound-crd-created-at"
 )
 
 // BoundAPIResource describes a bound GroupVersionResource through an APIResourceSchema of an APIExport..
@@ -46,6 +46,15 @@ type APIResourceSchema struct {
 	Spec APIResourceSchemaSpec `json:"spec,omitempty"`
 }
 
+// AnnotationReferencingAPIExportsKey is the annotation key on an APIResourceSchema listing the names of
+// the APIExports in the same workspace that reference it in spec.latestResourceSchemas, sorted and
+// comma-separated. It is maintained by kcp and absent if no APIExport references the schema.
+const AnnotationReferencingAPIExportsKey = "apis.kcp.io/referencing-apiexports"
+
+// AnnotationForceDeleteKey is the annotation key on an APIResourceSchema that, when set to "true", allows
+// deleting the schema although APIExports still reference it.
+const AnnotationForceDeleteKey = "apis.kcp.io/force-delete"
+
 // APIResourceSchemaSpec defines the desired state of APIResourceSchema.
 type APIResourceSchemaSpec struct {
 	// group is the API group of the defined custom resource. Empty string means the
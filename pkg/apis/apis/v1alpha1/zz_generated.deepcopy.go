@@ -101,6 +101,11 @@ func (in *APIBindingSpec) DeepCopyInto(out *APIBindingSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ResourceSelector != nil {
+		in, out := &in.ResourceSelector, &out.ResourceSelector
+		*out = make([]GroupResource, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -145,6 +150,18 @@ func (in *APIBindingStatus) DeepCopyInto(out *APIBindingStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.AcceptedPermissionClaims != nil {
+		in, out := &in.AcceptedPermissionClaims, &out.AcceptedPermissionClaims
+		*out = make([]PermissionClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SchemaBindings != nil {
+		in, out := &in.SchemaBindings, &out.SchemaBindings
+		*out = make([]SchemaBindingStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -848,6 +865,22 @@ func (in *ResourceSelector) DeepCopy() *ResourceSelector {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SchemaBindingStatus) DeepCopyInto(out *SchemaBindingStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SchemaBindingStatus.
+func (in *SchemaBindingStatus) DeepCopy() *SchemaBindingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SchemaBindingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VirtualWorkspace) DeepCopyInto(out *VirtualWorkspace) {
 	*out = *in
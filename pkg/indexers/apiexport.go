@@ -22,7 +22,10 @@ import (
 	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
 	"github.com/kcp-dev/logicalcluster/v3"
 
+	"k8s.io/client-go/tools/cache"
+
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/client"
 )
 
 const (
@@ -30,6 +33,9 @@ const (
 	APIExportByIdentity = "APIExportByIdentity"
 	// APIExportBySecret is the indexer name for retrieving APIExports by secret.
 	APIExportBySecret = "APIExportSecret"
+	// APIExportByAPIResourceSchema is the indexer name for retrieving APIExports by the cluster-aware
+	// keys of the APIResourceSchemas in their spec.latestResourceSchemas.
+	APIExportByAPIResourceSchema = "APIExportByAPIResourceSchema"
 )
 
 // IndexAPIExportByIdentity is an index function that indexes an APIExport by its identity hash.
@@ -39,9 +45,20 @@ func IndexAPIExportByIdentity(obj interface{}) ([]string, error) {
 		return []string{}, fmt.Errorf("obj %T is not an APIExport", obj)
 	}
 
+	if apiExport.Status.IdentityHash == "" {
+		return []string{}, nil
+	}
+
 	return []string{apiExport.Status.IdentityHash}, nil
 }
 
+// APIExportsByIdentityHash returns the APIExports with the given identity hash from an indexer
+// with the APIExportByIdentity index. Exports may share an identity, e.g. when an export is
+// copied to another workspace together with its identity secret, so all of them are returned.
+func APIExportsByIdentityHash(indexer cache.Indexer, identityHash string) ([]*apisv1alpha1.APIExport, error) {
+	return ByIndex[*apisv1alpha1.APIExport](indexer, APIExportByIdentity, identityHash)
+}
+
 // IndexAPIExportBySecret is an index function that indexes an APIExport by its identity secret references. Index values
 // are of the form <cluster name>|<secret reference namespace>/<secret reference name> (cache keys).
 func IndexAPIExportBySecret(obj interface{}) ([]string, error) {
@@ -65,3 +82,19 @@ func IndexAPIExportBySecret(obj interface{}) ([]string, error) {
 
 	return []string{kcpcache.ToClusterAwareKey(logicalcluster.From(apiExport).String(), ref.Namespace, ref.Name)}, nil
 }
+
+// IndexAPIExportByAPIResourceSchema is an index function that indexes an APIExport by its spec.latestResourceSchemas.
+// Index values are of the form <cluster path>|<schema name>.
+func IndexAPIExportByAPIResourceSchema(obj interface{}) ([]string, error) {
+	apiExport, ok := obj.(*apisv1alpha1.APIExport)
+	if !ok {
+		return []string{}, fmt.Errorf("obj %T is not an APIExport", obj)
+	}
+
+	ret := make([]string, len(apiExport.Spec.LatestResourceSchemas))
+	for i := range apiExport.Spec.LatestResourceSchemas {
+		ret[i] = client.ToClusterAwareKey(logicalcluster.From(apiExport).Path(), apiExport.Spec.LatestResourceSchemas[i])
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package indexers
+
+import (
+	"testing"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/client"
+)
+
+func TestAPIExportsByIdentityHash(t *testing.T) {
+	export := func(cluster, name, identityHash string) *apisv1alpha1.APIExport {
+		return &apisv1alpha1.APIExport{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					logicalcluster.AnnotationKey: cluster,
+				},
+				Name: name,
+			},
+			Status: apisv1alpha1.APIExportStatus{IdentityHash: identityHash},
+		}
+	}
+
+	indexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{
+		APIExportByIdentity: IndexAPIExportByIdentity,
+	})
+	for _, e := range []*apisv1alpha1.APIExport{
+		export("root:a", "widgets", "hash-a"),
+		export("root:b", "widgets", "hash-b"),
+		export("root:c", "shared", "hash-shared"),
+		export("root:d", "shared", "hash-shared"),
+		export("root:e", "not-ready", ""),
+	} {
+		require.NoError(t, indexer.Add(e))
+	}
+
+	tests := map[string]struct {
+		identityHash string
+		wantClusters []logicalcluster.Name
+	}{
+		"found": {
+			identityHash: "hash-a",
+			wantClusters: []logicalcluster.Name{"root:a"},
+		},
+		"same name, other identity": {
+			identityHash: "hash-b",
+			wantClusters: []logicalcluster.Name{"root:b"},
+		},
+		"unknown identity": {
+			identityHash: "unknown",
+		},
+		"exports without identity are not indexed": {
+			identityHash: "",
+		},
+		"shared identity": {
+			identityHash: "hash-shared",
+			wantClusters: []logicalcluster.Name{"root:c", "root:d"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := APIExportsByIdentityHash(indexer, tt.identityHash)
+			require.NoError(t, err)
+
+			var gotClusters []logicalcluster.Name
+			for _, export := range got {
+				require.Equal(t, tt.identityHash, export.Status.IdentityHash)
+				gotClusters = append(gotClusters, logicalcluster.From(export))
+			}
+			require.ElementsMatch(t, tt.wantClusters, gotClusters)
+		})
+	}
+}
+
+func TestIndexAPIExportByAPIResourceSchema(t *testing.T) {
+	tests := map[string]struct {
+		obj     interface{}
+		want    []string
+		wantErr bool
+	}{
+		"not an APIExport": {
+			obj:     "not an APIExport",
+			want:    []string{},
+			wantErr: true,
+		},
+		"valid APIExport": {
+			obj: &apisv1alpha1.APIExport{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey: "root:default",
+					},
+					Name: "foo",
+				},
+				Spec: apisv1alpha1.APIExportSpec{
+					LatestResourceSchemas: []string{"schema1", "some-other-schema"},
+				},
+			},
+			want: []string{
+				client.ToClusterAwareKey(logicalcluster.NewPath("root:default"), "schema1"),
+				client.ToClusterAwareKey(logicalcluster.NewPath("root:default"), "some-other-schema"),
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := IndexAPIExportByAPIResourceSchema(tt.obj)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
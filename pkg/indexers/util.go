@@ -18,6 +18,8 @@ package indexers
 
 import (
 	"fmt"
+	"reflect"
+	"runtime"
 
 	"k8s.io/client-go/tools/cache"
 )
@@ -58,3 +60,30 @@ func AddIfNotPresentOrDie(indexer cache.Indexer, toAdd cache.Indexers) {
 		panic(fmt.Errorf("error adding indexers: %w", err))
 	}
 }
+
+// RegisterOrVerify adds the index function fn under name to indexer. It is a no-op if the very same
+// function is already registered under that name, and returns an error if a different one is.
+func RegisterOrVerify(indexer cache.Indexer, name string, fn cache.IndexFunc) error {
+	if existing, found := indexer.GetIndexers()[name]; found {
+		if funcPointer(existing) == funcPointer(fn) {
+			return nil
+		}
+		return fmt.Errorf("indexer %q is already registered with %s, cannot register %s", name, funcName(existing), funcName(fn))
+	}
+
+	if err := indexer.AddIndexers(cache.Indexers{name: fn}); err != nil {
+		return fmt.Errorf("error adding indexer %q: %w", name, err)
+	}
+	return nil
+}
+
+func funcPointer(fn cache.IndexFunc) uintptr {
+	return reflect.ValueOf(fn).Pointer()
+}
+
+func funcName(fn cache.IndexFunc) string {
+	if f := runtime.FuncForPC(funcPointer(fn)); f != nil {
+		return f.Name()
+	}
+	return "<unknown>"
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package indexers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestRegisterOrVerify(t *testing.T) {
+	tests := map[string]struct {
+		existing     cache.Indexers
+		fn           cache.IndexFunc
+		wantIndexers int
+		wantErr      string
+	}{
+		"first registration": {
+			fn:           IndexByLogicalClusterPath,
+			wantIndexers: 1,
+		},
+		"same func registered twice": {
+			existing:     cache.Indexers{"test": IndexByLogicalClusterPath},
+			fn:           IndexByLogicalClusterPath,
+			wantIndexers: 1,
+		},
+		"conflicting func": {
+			existing: cache.Indexers{"test": IndexByLogicalClusterPath},
+			fn:       IndexByLogicalClusterPathAndName,
+			wantErr:  `indexer "test" is already registered with github.com/kcp-dev/kcp/pkg/indexers.IndexByLogicalClusterPath, cannot register github.com/kcp-dev/kcp/pkg/indexers.IndexByLogicalClusterPathAndName`,
+		},
+		"other indexers are kept": {
+			existing:     cache.Indexers{"other": IndexByLogicalClusterPathAndName},
+			fn:           IndexByLogicalClusterPath,
+			wantIndexers: 2,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			require.NoError(t, indexer.AddIndexers(tt.existing))
+
+			err := RegisterOrVerify(indexer, "test", tt.fn)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			registered := indexer.GetIndexers()
+			require.Len(t, registered, tt.wantIndexers)
+			require.Equal(t, funcPointer(tt.fn), funcPointer(registered["test"]))
+		})
+	}
+}
@@ -37,6 +37,9 @@ const (
 	// QueueKeyKey is used to expose the workqueue key being processed.
 	QueueKeyKey = "key"
 
+	// LogicalClusterKey is used to specify the logical cluster a log is related to.
+	LogicalClusterKey = "logical_cluster"
+
 	// WorkspaceKey is used to specify a workspace when a log is related to an object.
 	WorkspaceKey = "workspace"
 	// NamespaceKey is used to specify a namespace when a log is related to an object.
@@ -57,6 +60,16 @@ func WithQueueKey(logger logr.Logger, key string) logr.Logger {
 	return logger.WithValues(QueueKeyKey, key)
 }
 
+// WithLogicalCluster adds the logical cluster name to the logger.
+func WithLogicalCluster(logger logr.Logger, clusterName logicalcluster.Name) logr.Logger {
+	return logger.WithValues(LogicalClusterKey, clusterName.String())
+}
+
+// WithClusterAndObject adds the logical cluster of the object and the object identifiers to the logger.
+func WithClusterAndObject(logger logr.Logger, obj Object) logr.Logger {
+	return WithObject(WithLogicalCluster(logger, logicalcluster.From(obj)), obj)
+}
+
 type Object interface {
 	metav1.Object
 	runtime.Object
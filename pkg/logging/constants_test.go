@@ -0,0 +1,66 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWithLogicalCluster(t *testing.T) {
+	obj := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        "cm",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+		},
+	}
+
+	tests := map[string]struct {
+		withValues func(logger logr.Logger) logr.Logger
+		want       string
+	}{
+		"cluster": {
+			withValues: func(logger logr.Logger) logr.Logger {
+				return WithLogicalCluster(logger, "root:org")
+			},
+			want: `"level"=0 "msg"="test" "logical_cluster"="root:org"`,
+		},
+		"cluster and object": {
+			withValues: func(logger logr.Logger) logr.Logger {
+				return WithClusterAndObject(logger, obj)
+			},
+			want: `"level"=0 "msg"="test" "logical_cluster"="root:org" "configmap.workspace"="root:org" "configmap.namespace"="ns" "configmap.name"="cm" "configmap.apiVersion"=""`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got string
+			logger := funcr.New(func(prefix, args string) { got = args }, funcr.Options{})
+			tc.withValues(logger).Info("test")
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -78,6 +78,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.MaximalPermissionPolicy":                     schema_pkg_apis_apis_v1alpha1_MaximalPermissionPolicy(ref),
 		"github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.PermissionClaim":                             schema_pkg_apis_apis_v1alpha1_PermissionClaim(ref),
 		"github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.ResourceSelector":                            schema_pkg_apis_apis_v1alpha1_ResourceSelector(ref),
+		"github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.SchemaBindingStatus":                         schema_pkg_apis_apis_v1alpha1_SchemaBindingStatus(ref),
 		"github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.VirtualWorkspace":                            schema_pkg_apis_apis_v1alpha1_VirtualWorkspace(ref),
 		"github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1.LogicalCluster":                              schema_pkg_apis_core_v1alpha1_LogicalCluster(ref),
 		"github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1.LogicalClusterList":                          schema_pkg_apis_core_v1alpha1_LogicalClusterList(ref),
@@ -1202,12 +1203,33 @@ func schema_pkg_apis_apis_v1alpha1_APIBindingSpec(ref common.ReferenceCallback)
 							},
 						},
 					},
+					"exportGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "exportGeneration pins the binding to the given metadata.generation of the referenced APIExport. If set, the APIs are not bound (or re-bound) while the APIExport has a different generation, and the APIExportPinned condition is false. Unlike the reference, it can be changed to roll forward. The generation is used because, unlike the resourceVersion, it is the same on every shard and in the cache server, and it does not change on status updates.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"resourceSelector": {
+						SchemaProps: spec.SchemaProps{
+							Description: "resourceSelector restricts the APIs that are bound to the given resources of the referenced APIExport. All APIs of the APIExport are bound if it is empty. Selecting a resource that the APIExport does not export fails the binding. Deselecting a resource that is already bound does not unbind it.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.GroupResource"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"reference"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.AcceptablePermissionClaim", "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.BindingReference"},
+			"github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.AcceptablePermissionClaim", "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.BindingReference", "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.GroupResource"},
 	}
 }
 
@@ -1290,11 +1312,62 @@ func schema_pkg_apis_apis_v1alpha1_APIBindingStatus(ref common.ReferenceCallback
 							},
 						},
 					},
+					"acceptedPermissionClaims": {
+						SchemaProps: spec.SchemaProps{
+							Description: "acceptedPermissionClaims records the permission claims of the APIExport that are accepted in spec.permissionClaims. The APIBinding is not bound until every claim of the APIExport is either accepted or rejected.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.PermissionClaim"),
+									},
+								},
+							},
+						},
+					},
+					"apiExportGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "apiExportGeneration is the generation of the APIExport the binding was last successfully bound to. Compare it with the APIExport to see whether the binding is behind. Unlike the resourceVersion, the generation is the same on every shard the APIExport is replicated to.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"schemaBindings": {
+						VendorExtensible: spec.VendorExtensible{
+							Extensions: spec.Extensions{
+								"x-kubernetes-list-map-keys": []interface{}{
+									"group",
+									"resource",
+								},
+								"x-kubernetes-list-type": "map",
+							},
+						},
+						SchemaProps: spec.SchemaProps{
+							Description: "schemaBindings records the binding state of each APIResourceSchema of the bound APIExport, including the ones that are not bound (yet).",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.SchemaBindingStatus"),
+									},
+								},
+							},
+						},
+					},
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "observedGeneration is the generation of the APIBinding spec the controller last completed binding for. The status is stale if it is less than metadata.generation.",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.BoundAPIResource", "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.PermissionClaim", "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1.Condition"},
+			"github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.BoundAPIResource", "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.PermissionClaim", "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1.SchemaBindingStatus", "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1.Condition"},
 	}
 }
 
@@ -2542,6 +2615,66 @@ func schema_pkg_apis_apis_v1alpha1_ResourceSelector(ref common.ReferenceCallback
 	}
 }
 
+func schema_pkg_apis_apis_v1alpha1_SchemaBindingStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SchemaBindingStatus describes the binding state of a single APIResourceSchema of the bound APIExport.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"group": {
+						SchemaProps: spec.SchemaProps{
+							Description: "group is the group of the API. Empty string for the core API group.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resource": {
+						SchemaProps: spec.SchemaProps{
+							Description: "resource is the resource of the API.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"schema": {
+						SchemaProps: spec.SchemaProps{
+							Description: "schema is the name of the APIResourceSchema.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"crdName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "crdName is the name of the bound CRD for the schema.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "state is the binding state of the schema.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "message is a human readable message with details about the state.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"group", "resource", "schema", "state"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_apis_v1alpha1_VirtualWorkspace(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
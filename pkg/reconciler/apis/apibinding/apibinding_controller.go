@@ -24,6 +24,7 @@ import (
 	"github.com/go-logr/logr"
 	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
 	kcpdynamic "github.com/kcp-dev/client-go/dynamic"
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
 	"github.com/kcp-dev/logicalcluster/v3"
 
 	"k8s.io/apiextensions-apiserver/pkg/apihelpers"
@@ -34,17 +35,22 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/apis/core"
 	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+	kcpscheme "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/scheme"
 	apisv1alpha1client "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/typed/apis/v1alpha1"
 	apisv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/indexers"
@@ -55,15 +61,31 @@ import (
 
 const (
 	ControllerName = "kcp-apibinding"
+
+	// deletedCRDTrackerTTL is how long a deleted bound CRD is remembered, i.e. how long the CRD informer
+	// may lag behind before a deleted CRD is no longer recreated.
+	deletedCRDTrackerTTL = 5 * time.Minute
+
+	// fanOutCoalescingWindow is how long an APIExport or APIResourceSchema state is remembered as fanned out.
+	fanOutCoalescingWindow = time.Minute
+
+	// crdCreationConcurrency is how many API groups of a single APIBinding get their bound CRDs created in parallel.
+	crdCreationConcurrency = 10
+
+	// awaitingEstablishedTTL is how long a created bound CRD is waited for to become Established
+	// before it is no longer observed in the established duration metric.
+	awaitingEstablishedTTL = time.Hour
 )
 
 var (
+	// SystemBoundCRDsClusterName is the default logical cluster bound CRDs are created in.
 	SystemBoundCRDsClusterName = logicalcluster.Name("system:bound-crds")
 )
 
 // NewController returns a new controller for APIBindings.
 func NewController(
 	crdClusterClient kcpapiextensionsclientset.ClusterInterface,
+	kubeClusterClient kcpkubernetesclientset.ClusterInterface,
 	kcpClusterClient kcpclientset.ClusterInterface,
 	dynamicClusterClient kcpdynamic.ClusterInterface,
 	dynamicDiscoverySharedInformerFactory *informer.DiscoveringDynamicSharedInformerFactory,
@@ -75,9 +97,18 @@ func NewController(
 	globalAPIResourceSchemaInformer apisv1alpha1informers.APIResourceSchemaClusterInformer,
 	globalAPIConversionInformer apisv1alpha1informers.APIConversionClusterInformer,
 	crdInformer kcpapiextensionsv1informers.CustomResourceDefinitionClusterInformer,
+	options Options,
 ) (*controller, error) {
+	boundCRDsClusterName := options.BoundCRDsClusterName
+	if boundCRDsClusterName.Empty() {
+		boundCRDsClusterName = SystemBoundCRDsClusterName
+	}
+
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
 
+	eventBroadcaster := record.NewBroadcaster()
+	eventRecorder, eventSink := newClusterAwareEventRecorder(eventBroadcaster, kcpscheme.Scheme, kubeClusterClient)
+
 	c := &controller{
 		queue:                queue,
 		crdClusterClient:     crdClusterClient,
@@ -86,22 +117,7 @@ func NewController(
 		ddsif:                dynamicDiscoverySharedInformerFactory,
 
 		listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
-			list, err := apiBindingInformer.Lister().List(labels.Everything())
-			if err != nil {
-				return nil, err
-			}
-
-			var ret []*apisv1alpha1.APIBinding
-
-			for i := range list {
-				if logicalcluster.From(list[i]) != clusterName {
-					continue
-				}
-
-				ret = append(ret, list[i])
-			}
-
-			return ret, nil
+			return apiBindingInformer.Lister().Cluster(clusterName).List(labels.Everything())
 		},
 		listAPIBindingsByAPIExport: func(export *apisv1alpha1.APIExport) ([]*apisv1alpha1.APIBinding, error) {
 			// binding keys by full path
@@ -138,33 +154,20 @@ func NewController(
 			return apiBindingInformer.Lister().Cluster(clusterName).Get(name)
 		},
 
-		getAPIExport: func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
-			// Try local informer first
-			export, err := indexers.ByPathAndName[*apisv1alpha1.APIExport](apisv1alpha1.Resource("apiexports"), apiExportInformer.Informer().GetIndexer(), path, name)
-			if err == nil {
-				// Quick happy path - found it locally
-				return export, nil
-			}
-			if !apierrors.IsNotFound(err) {
-				// Unrecoverable error
-				return nil, err
-			}
-			// Didn't find it locally - try remote
-			return indexers.ByPathAndName[*apisv1alpha1.APIExport](apisv1alpha1.Resource("apiexports"), globalAPIExportInformer.Informer().GetIndexer(), path, name)
-		},
+		getAPIExport: getAPIExportFromIndexers(apiExportInformer.Informer().GetIndexer(), globalAPIExportInformer.Informer().GetIndexer()),
 		getAPIExportsBySchema: func(schema *apisv1alpha1.APIResourceSchema) ([]*apisv1alpha1.APIExport, error) {
 			key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(schema)
 			if err != nil {
 				return nil, err
 			}
-			exports, err := indexers.ByIndex[*apisv1alpha1.APIExport](apiExportInformer.Informer().GetIndexer(), indexAPIExportsByAPIResourceSchema, key)
+			exports, err := indexers.ByIndex[*apisv1alpha1.APIExport](apiExportInformer.Informer().GetIndexer(), indexers.APIExportByAPIResourceSchema, key)
 			if err != nil {
 				return nil, err
 			}
 			if len(exports) > 0 {
 				return exports, nil
 			}
-			return indexers.ByIndex[*apisv1alpha1.APIExport](globalAPIExportInformer.Informer().GetIndexer(), indexAPIExportsByAPIResourceSchema, key)
+			return indexers.ByIndex[*apisv1alpha1.APIExport](globalAPIExportInformer.Informer().GetIndexer(), indexers.APIExportByAPIResourceSchema, key)
 		},
 
 		getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
@@ -186,32 +189,72 @@ func NewController(
 		createCRD: func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
 			return crdClusterClient.Cluster(clusterName).ApiextensionsV1().CustomResourceDefinitions().Create(ctx, crd, metav1.CreateOptions{})
 		},
+		updateCRD: func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return crdClusterClient.Cluster(clusterName).ApiextensionsV1().CustomResourceDefinitions().Update(ctx, crd, metav1.UpdateOptions{})
+		},
 		getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
 			return crdInformer.Lister().Cluster(clusterName).Get(name)
 		},
+		getLiveCRD: func(ctx context.Context, clusterName logicalcluster.Path, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return crdClusterClient.Cluster(clusterName).ApiextensionsV1().CustomResourceDefinitions().Get(ctx, name, metav1.GetOptions{})
+		},
 		listCRDs: func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error) {
 			return crdInformer.Lister().Cluster(clusterName).List(labels.Everything())
 		},
-		deletedCRDTracker: newLockedStringSet(),
-		commit:            committer.NewCommitter[*APIBinding, Patcher, *APIBindingSpec, *APIBindingStatus](kcpClusterClient.ApisV1alpha1().APIBindings()),
+		deletedCRDTracker:   newLockedStringSet(deletedCRDTrackerTTL),
+		awaitingEstablished: newLockedStringSet(awaitingEstablishedTTL),
+		clock:               clock.RealClock{},
+		recentFanOuts:       utilcache.NewExpiring(),
+		eventBroadcaster:    eventBroadcaster,
+		eventSink:           eventSink,
+		eventRecorder:       eventRecorder,
+		commit:              committer.NewCommitter[*APIBinding, Patcher, *APIBindingSpec, *APIBindingStatus](kcpClusterClient.ApisV1alpha1().APIBindings(), committer.WithFieldManager(ControllerName)),
+
+		crdCreationConcurrency:     crdCreationConcurrency,
+		propagatedMetadataPrefixes: options.PropagatedMetadataPrefixes,
+		removedSchemaPolicy:        RemovedSchemaPolicy(options.RemovedSchemaPolicy),
+		repairDriftedSubresources:  options.RepairDriftedSubresources,
+		boundCRDsClusterName:       boundCRDsClusterName,
+		isLogicalClusterDeleting:   options.IsLogicalClusterDeleting,
 	}
 
 	logger := logging.WithReconciler(klog.Background(), ControllerName)
 
-	// APIBinding indexers
-	indexers.AddIfNotPresentOrDie(apiBindingInformer.Informer().GetIndexer(), cache.Indexers{
-		indexers.APIBindingsByAPIExport: indexers.IndexAPIBindingByAPIExport,
-	})
+	c.enqueueAfter = func(apiBinding *apisv1alpha1.APIBinding, duration time.Duration) {
+		key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(apiBinding)
+		if err != nil {
+			utilruntime.HandleError(err)
+			return
+		}
+		logging.WithQueueKey(logger, key).V(4).Info("queueing APIBinding", "after", duration)
+		queue.AddAfter(key, duration)
+	}
 
-	// APIExport indexers
-	indexers.AddIfNotPresentOrDie(apiExportInformer.Informer().GetIndexer(), cache.Indexers{
-		indexers.ByLogicalClusterPathAndName: indexers.IndexByLogicalClusterPathAndName,
-		indexAPIExportsByAPIResourceSchema:   indexAPIExportsByAPIResourceSchemasFunc,
-	})
-	indexers.AddIfNotPresentOrDie(globalAPIExportInformer.Informer().GetIndexer(), cache.Indexers{
-		indexers.ByLogicalClusterPathAndName: indexers.IndexByLogicalClusterPathAndName,
-		indexAPIExportsByAPIResourceSchema:   indexAPIExportsByAPIResourceSchemasFunc,
-	})
+	for _, toRegister := range []struct {
+		indexer  cache.Indexer
+		indexers cache.Indexers
+	}{
+		// APIBinding indexers
+		{apiBindingInformer.Informer().GetIndexer(), cache.Indexers{
+			indexers.APIBindingsByAPIExport:      indexers.IndexAPIBindingByAPIExport,
+			indexAPIBindingsByBoundGroupResource: indexAPIBindingsByBoundGroupResourceFunc,
+		}},
+		// APIExport indexers
+		{apiExportInformer.Informer().GetIndexer(), cache.Indexers{
+			indexers.ByLogicalClusterPathAndName:  indexers.IndexByLogicalClusterPathAndName,
+			indexers.APIExportByAPIResourceSchema: indexers.IndexAPIExportByAPIResourceSchema,
+		}},
+		{globalAPIExportInformer.Informer().GetIndexer(), cache.Indexers{
+			indexers.ByLogicalClusterPathAndName:  indexers.IndexByLogicalClusterPathAndName,
+			indexers.APIExportByAPIResourceSchema: indexers.IndexAPIExportByAPIResourceSchema,
+		}},
+	} {
+		for name, fn := range toRegister.indexers {
+			if err := indexers.RegisterOrVerify(toRegister.indexer, name, fn); err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	// APIBinding handlers
 	apiBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -226,7 +269,7 @@ func NewController(
 	crdInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: func(obj interface{}) bool {
 			crd := obj.(*apiextensionsv1.CustomResourceDefinition)
-			return logicalcluster.From(crd) == SystemBoundCRDsClusterName
+			return logicalcluster.From(crd) == c.boundCRDsClusterName
 		},
 		Handler: cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
@@ -242,6 +285,7 @@ func NewController(
 				// we know we need to recreate it. This set is there to fight against stale informers still seeing
 				// the deleted CRD.
 				c.deletedCRDTracker.Add(crd.Name)
+				c.awaitingEstablished.Remove(crd.Name)
 
 				c.enqueueCRD(crd, logger)
 			},
@@ -250,23 +294,31 @@ func NewController(
 
 	// APIExport handlers
 	apiExportInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc:    func(obj interface{}) { c.enqueueAPIExport(objOrTombstone[*apisv1alpha1.APIExport](obj), logger, "") },
-		UpdateFunc: func(_, obj interface{}) { c.enqueueAPIExport(objOrTombstone[*apisv1alpha1.APIExport](obj), logger, "") },
+		AddFunc: func(obj interface{}) {
+			c.enqueueAPIExportCoalesced("local", objOrTombstone[*apisv1alpha1.APIExport](obj), logger)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			c.enqueueAPIExportCoalesced("local", objOrTombstone[*apisv1alpha1.APIExport](obj), logger)
+		},
 		DeleteFunc: func(obj interface{}) { c.enqueueAPIExport(objOrTombstone[*apisv1alpha1.APIExport](obj), logger, "") },
 	})
 	globalAPIExportInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
-		AddFunc:    func(obj interface{}) { c.enqueueAPIExport(objOrTombstone[*apisv1alpha1.APIExport](obj), logger, "") },
-		UpdateFunc: func(_, obj interface{}) { c.enqueueAPIExport(objOrTombstone[*apisv1alpha1.APIExport](obj), logger, "") },
+		AddFunc: func(obj interface{}) {
+			c.enqueueAPIExportCoalesced("global", objOrTombstone[*apisv1alpha1.APIExport](obj), logger)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			c.enqueueAPIExportCoalesced("global", objOrTombstone[*apisv1alpha1.APIExport](obj), logger)
+		},
 		DeleteFunc: func(obj interface{}) { c.enqueueAPIExport(objOrTombstone[*apisv1alpha1.APIExport](obj), logger, "") },
 	})
 
 	// APIResourceSchema handlers
 	apiResourceSchemaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			c.enqueueAPIResourceSchema(objOrTombstone[*apisv1alpha1.APIResourceSchema](obj), logger, "")
+			c.enqueueAPIResourceSchemaCoalesced("local", objOrTombstone[*apisv1alpha1.APIResourceSchema](obj), logger)
 		},
 		UpdateFunc: func(_, obj interface{}) {
-			c.enqueueAPIResourceSchema(objOrTombstone[*apisv1alpha1.APIResourceSchema](obj), logger, "")
+			c.enqueueAPIResourceSchemaCoalesced("local", objOrTombstone[*apisv1alpha1.APIResourceSchema](obj), logger)
 		},
 		DeleteFunc: func(obj interface{}) {
 			c.enqueueAPIResourceSchema(objOrTombstone[*apisv1alpha1.APIResourceSchema](obj), logger, "")
@@ -274,10 +326,10 @@ func NewController(
 	})
 	globalAPIResourceSchemaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
-			c.enqueueAPIResourceSchema(objOrTombstone[*apisv1alpha1.APIResourceSchema](obj), logger, "")
+			c.enqueueAPIResourceSchemaCoalesced("global", objOrTombstone[*apisv1alpha1.APIResourceSchema](obj), logger)
 		},
 		UpdateFunc: func(_, obj interface{}) {
-			c.enqueueAPIResourceSchema(objOrTombstone[*apisv1alpha1.APIResourceSchema](obj), logger, "")
+			c.enqueueAPIResourceSchemaCoalesced("global", objOrTombstone[*apisv1alpha1.APIResourceSchema](obj), logger)
 		},
 		DeleteFunc: func(obj interface{}) {
 			c.enqueueAPIResourceSchema(objOrTombstone[*apisv1alpha1.APIResourceSchema](obj), logger, "")
@@ -300,6 +352,25 @@ func NewController(
 	return c, nil
 }
 
+// getAPIExportFromIndexers returns a function that looks up an APIExport in the local indexer,
+// and in the global (cache server) indexer if it is not found locally.
+func getAPIExportFromIndexers(local, global cache.Indexer) func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+	return func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+		// Try local informer first
+		export, err := indexers.ByPathAndName[*apisv1alpha1.APIExport](apisv1alpha1.Resource("apiexports"), local, path, name)
+		if err == nil {
+			// Quick happy path - found it locally
+			return export, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			// Unrecoverable error
+			return nil, err
+		}
+		// Didn't find it locally - try remote
+		return indexers.ByPathAndName[*apisv1alpha1.APIExport](apisv1alpha1.Resource("apiexports"), global, path, name)
+	}
+}
+
 func objOrTombstone[T runtime.Object](obj any) T {
 	if t, ok := obj.(T); ok {
 		return t
@@ -345,11 +416,56 @@ type controller struct {
 	getAPIConversion func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIConversion, error)
 
 	createCRD func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error)
+	updateCRD func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error)
 	getCRD    func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error)
 	listCRDs  func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error)
 
+	// getLiveCRD reads a CRD from the server instead of the informer cache, for when the
+	// cache is known to be behind, e.g. after an update conflict.
+	getLiveCRD func(ctx context.Context, clusterName logicalcluster.Path, name string) (*apiextensionsv1.CustomResourceDefinition, error)
+
 	deletedCRDTracker *lockedStringSet
+	eventRecorder     events.EventRecorder
 	commit            CommitFunc
+
+	// eventBroadcaster sends the events of the eventRecorder to the eventSink while the controller runs.
+	eventBroadcaster record.EventBroadcaster
+	eventSink        record.EventSink
+
+	// enqueueAfter enqueues an APIBinding again after the given duration. It is nil outside of the controller.
+	enqueueAfter func(apiBinding *apisv1alpha1.APIBinding, duration time.Duration)
+
+	// recentFanOuts records the APIExport and APIResourceSchema states that were recently mapped to APIBindings.
+	recentFanOuts *utilcache.Expiring
+
+	// crdCreationConcurrency is the number of API groups whose bound CRDs are created in parallel for one APIBinding.
+	crdCreationConcurrency int
+
+	// awaitingEstablished holds the names of the bound CRDs created by this controller that have
+	// not been observed Established yet.
+	awaitingEstablished *lockedStringSet
+
+	// clock is used for the time-based decisions of the reconciler.
+	clock clock.PassiveClock
+
+	// propagatedMetadataPrefixes are the prefixes of the label and annotation keys of
+	// APIResourceSchemas that are copied onto their bound CRDs.
+	propagatedMetadataPrefixes []string
+
+	// removedSchemaPolicy is what happens to bound resources that were removed from the APIExport.
+	// They are retained if empty.
+	removedSchemaPolicy RemovedSchemaPolicy
+
+	// repairDriftedSubresources is whether the subresources of bound CRDs that drifted from
+	// their APIResourceSchema are repaired.
+	repairDriftedSubresources bool
+
+	// boundCRDsClusterName is the logical cluster bound CRDs are created in.
+	boundCRDsClusterName logicalcluster.Name
+
+	// isLogicalClusterDeleting returns whether a logical cluster is being deleted. Its APIBindings are
+	// not reconciled then. A nil func treats all logical clusters as not deleting.
+	isLogicalClusterDeleting func(clusterName logicalcluster.Name) bool
 }
 
 // enqueueAPIBinding enqueues an APIBinding .
@@ -360,6 +476,7 @@ func (c *controller) enqueueAPIBinding(apiBinding *apisv1alpha1.APIBinding, logg
 		return
 	}
 
+	logger = logging.WithLogicalCluster(logger, logicalcluster.From(apiBinding))
 	logging.WithQueueKey(logger, key).V(2).Info(fmt.Sprintf("queueing APIBinding%s", logSuffix))
 	c.queue.Add(key)
 }
@@ -391,6 +508,11 @@ func (c *controller) enqueueCRD(crd *apiextensionsv1.CustomResourceDefinition, l
 
 	clusterName := logicalcluster.Name(crd.Annotations[apisv1alpha1.AnnotationSchemaClusterKey])
 	apiResourceSchema, err := c.getAPIResourceSchema(clusterName, crd.Annotations[apisv1alpha1.AnnotationSchemaNameKey])
+	if apierrors.IsNotFound(err) {
+		// the CRD outlived its schema, and will be deleted by crdcleanup once no APIBinding uses it anymore
+		logger.V(4).Info("skipping CRD because its APIResourceSchema does not exist anymore")
+		return
+	}
 	if err != nil {
 		utilruntime.HandleError(err)
 		return
@@ -416,6 +538,39 @@ func (c *controller) enqueueAPIResourceSchema(schema *apisv1alpha1.APIResourceSc
 	}
 }
 
+// enqueueAPIExportCoalesced is enqueueAPIExport for informer events, skipping the APIBinding lookup if the
+// APIExport in the same state has been fanned out recently.
+func (c *controller) enqueueAPIExportCoalesced(source string, export *apisv1alpha1.APIExport, logger logr.Logger) {
+	if c.fannedOutRecently(source, export) {
+		logging.WithObject(logger, export).V(5).Info("skipping APIExport, already fanned out")
+		return
+	}
+	c.enqueueAPIExport(export, logger, "")
+}
+
+// enqueueAPIResourceSchemaCoalesced is enqueueAPIResourceSchema for informer events, skipping the APIExport
+// lookup if the APIResourceSchema in the same state has been fanned out recently.
+func (c *controller) enqueueAPIResourceSchemaCoalesced(source string, schema *apisv1alpha1.APIResourceSchema, logger logr.Logger) {
+	if c.fannedOutRecently(source, schema) {
+		logging.WithObject(logger, schema).V(5).Info("skipping APIResourceSchema, already fanned out")
+		return
+	}
+	c.enqueueAPIResourceSchema(schema, logger, "")
+}
+
+// fannedOutRecently returns true if obj with its current resourceVersion has been seen from source within the
+// coalescing window, and records it otherwise. Seeing the same object state again means the informer cache
+// did not change in between, so the APIBindings enqueued the first time see the same state anyway. Deletions
+// must never be coalesced, as they change the cache without changing the resourceVersion.
+func (c *controller) fannedOutRecently(source string, obj logging.Object) bool {
+	key := fmt.Sprintf("%s|%T|%s|%s", source, obj, kcpcache.ToClusterAwareKey(logicalcluster.From(obj).String(), "", obj.GetName()), obj.GetResourceVersion())
+	if _, found := c.recentFanOuts.Get(key); found {
+		return true
+	}
+	c.recentFanOuts.Set(key, struct{}{}, fanOutCoalescingWindow)
+	return false
+}
+
 func (c *controller) enqueueAPIConversion(apiConversion *apisv1alpha1.APIConversion, logger logr.Logger) {
 	logger = logging.WithObject(logger, apiConversion)
 
@@ -441,6 +596,14 @@ func (c *controller) Start(ctx context.Context, numThreads int) {
 	logger.Info("Starting controller")
 	defer logger.Info("Shutting down controller")
 
+	c.deletedCRDTracker.StartSweeper(ctx)
+	c.awaitingEstablished.StartSweeper(ctx)
+
+	if c.eventBroadcaster != nil {
+		c.eventBroadcaster.StartRecordingToSink(c.eventSink)
+		defer c.eventBroadcaster.Shutdown()
+	}
+
 	for i := 0; i < numThreads; i++ {
 		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
 	}
@@ -489,6 +652,10 @@ func (c *controller) process(ctx context.Context, key string) (bool, error) {
 		utilruntime.HandleError(err)
 		return false, nil
 	}
+	if c.isLogicalClusterDeleting != nil && c.isLogicalClusterDeleting(clusterName) {
+		logger.V(4).Info("skipping APIBinding in deleting logical cluster")
+		return false, nil
+	}
 
 	binding, err := c.getAPIBinding(clusterName, name)
 	if err != nil {
@@ -0,0 +1,248 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpfakeclient "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster/fake"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+	apisv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+)
+
+type fanOutLookups struct {
+	bindingsByExport int
+	exportsBySchema  int
+}
+
+func newFanOutTestController(lookups *fanOutLookups, export *apisv1alpha1.APIExport) *controller {
+	return &controller{
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+		listAPIBindingsByAPIExport: func(*apisv1alpha1.APIExport) ([]*apisv1alpha1.APIBinding, error) {
+			lookups.bindingsByExport++
+			return []*apisv1alpha1.APIBinding{
+				{ObjectMeta: metav1.ObjectMeta{Name: "binding", Annotations: map[string]string{logicalcluster.AnnotationKey: "root:consumer"}}},
+			}, nil
+		},
+		getAPIExportsBySchema: func(*apisv1alpha1.APIResourceSchema) ([]*apisv1alpha1.APIExport, error) {
+			lookups.exportsBySchema++
+			return []*apisv1alpha1.APIExport{export}, nil
+		},
+		recentFanOuts: utilcache.NewExpiring(),
+	}
+}
+
+func TestEnqueueCoalescing(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "export", ResourceVersion: "1", Annotations: map[string]string{logicalcluster.AnnotationKey: "root:provider"}},
+	}
+	schema := &apisv1alpha1.APIResourceSchema{
+		ObjectMeta: metav1.ObjectMeta{Name: "schema", ResourceVersion: "1", Annotations: map[string]string{logicalcluster.AnnotationKey: "root:provider"}},
+	}
+	logger := klog.Background()
+
+	lookups := &fanOutLookups{}
+	c := newFanOutTestController(lookups, export)
+	defer c.queue.ShutDown()
+
+	// a burst of events for the same object state results in a single lookup
+	for i := 0; i < 10; i++ {
+		c.enqueueAPIExportCoalesced("local", export, logger)
+		c.enqueueAPIResourceSchemaCoalesced("local", schema, logger)
+	}
+	require.Equal(t, 2, lookups.bindingsByExport, "one for the export, one through the schema")
+	require.Equal(t, 1, lookups.exportsBySchema)
+	require.Equal(t, 1, c.queue.Len())
+
+	// the same state seen through another informer is not coalesced
+	c.enqueueAPIExportCoalesced("global", export, logger)
+	require.Equal(t, 3, lookups.bindingsByExport)
+
+	// a new state is fanned out again
+	updated := export.DeepCopy()
+	updated.ResourceVersion = "2"
+	c.enqueueAPIExportCoalesced("local", updated, logger)
+	require.Equal(t, 4, lookups.bindingsByExport)
+
+	// deletions are never coalesced
+	c.enqueueAPIExport(updated, logger, "")
+	require.Equal(t, 5, lookups.bindingsByExport)
+}
+
+func BenchmarkEnqueueAPIExportBurst(b *testing.B) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "export", ResourceVersion: "1", Annotations: map[string]string{logicalcluster.AnnotationKey: "root:provider"}},
+	}
+	logger := klog.Background()
+
+	for name, enqueue := range map[string]func(c *controller){
+		"uncoalesced": func(c *controller) { c.enqueueAPIExport(export, logger, "") },
+		"coalesced":   func(c *controller) { c.enqueueAPIExportCoalesced("local", export, logger) },
+	} {
+		b.Run(name, func(b *testing.B) {
+			lookups := &fanOutLookups{}
+			c := newFanOutTestController(lookups, export)
+			defer c.queue.ShutDown()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				enqueue(c)
+			}
+			b.ReportMetric(float64(lookups.bindingsByExport)/float64(b.N), "lookups/op")
+		})
+	}
+}
+
+func newAPIBindingInformerWithBindings(t testing.TB, clusters, bindingsPerCluster int) apisv1alpha1informers.APIBindingClusterInformer {
+	informer := kcpinformers.NewSharedInformerFactory(kcpfakeclient.NewSimpleClientset(), time.Hour).Apis().V1alpha1().APIBindings()
+	for c := 0; c < clusters; c++ {
+		for b := 0; b < bindingsPerCluster; b++ {
+			require.NoError(t, informer.Informer().GetIndexer().Add(&apisv1alpha1.APIBinding{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        fmt.Sprintf("binding-%d", b),
+					Annotations: map[string]string{logicalcluster.AnnotationKey: fmt.Sprintf("cluster-%d", c)},
+				},
+			}))
+		}
+	}
+	return informer
+}
+
+func TestListAPIBindingsByCluster(t *testing.T) {
+	informer := newAPIBindingInformerWithBindings(t, 3, 2)
+
+	for _, clusterName := range []logicalcluster.Name{"cluster-0", "cluster-1", "cluster-2"} {
+		bindings, err := informer.Lister().Cluster(clusterName).List(labels.Everything())
+		require.NoError(t, err)
+
+		var names []string
+		for _, binding := range bindings {
+			require.Equal(t, clusterName, logicalcluster.From(binding))
+			names = append(names, binding.Name)
+		}
+		require.ElementsMatch(t, []string{"binding-0", "binding-1"}, names)
+	}
+
+	bindings, err := informer.Lister().Cluster("unknown").List(labels.Everything())
+	require.NoError(t, err)
+	require.Empty(t, bindings)
+}
+
+func BenchmarkListAPIBindings(b *testing.B) {
+	informer := newAPIBindingInformerWithBindings(b, 1000, 10)
+	clusterName := logicalcluster.Name("cluster-500")
+
+	b.Run("linear scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			list, err := informer.Lister().List(labels.Everything())
+			if err != nil {
+				b.Fatal(err)
+			}
+			var ret []*apisv1alpha1.APIBinding
+			for _, binding := range list {
+				if logicalcluster.From(binding) == clusterName {
+					ret = append(ret, binding)
+				}
+			}
+		}
+	})
+
+	b.Run("cluster index", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := informer.Lister().Cluster(clusterName).List(labels.Everything()); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestEnqueueCRDWithDeletedSchema(t *testing.T) {
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "todaywidgetsuid",
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey:            SystemBoundCRDsClusterName.String(),
+				apisv1alpha1.AnnotationSchemaClusterKey: "root:provider",
+				apisv1alpha1.AnnotationSchemaNameKey:    "today.widgets.kcp.io",
+			},
+		},
+	}
+
+	var handledErrors []error
+	originalHandlers := utilruntime.ErrorHandlers
+	utilruntime.ErrorHandlers = []func(error){func(err error) { handledErrors = append(handledErrors, err) }}
+	defer func() { utilruntime.ErrorHandlers = originalHandlers }()
+
+	c := &controller{
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+		getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+			return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apiresourceschemas"), name)
+		},
+	}
+	defer c.queue.ShutDown()
+
+	c.enqueueCRD(crd, klog.Background())
+	require.Empty(t, handledErrors)
+	require.Zero(t, c.queue.Len())
+
+	// other errors are still reported
+	c.getAPIResourceSchema = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+		return nil, errors.New("boom")
+	}
+	c.enqueueCRD(crd, klog.Background())
+	require.Len(t, handledErrors, 1)
+	require.Zero(t, c.queue.Len())
+}
+
+func TestProcessSkipsDeletingLogicalCluster(t *testing.T) {
+	var lookups []logicalcluster.Name
+	c := &controller{
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+		getAPIBinding: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIBinding, error) {
+			lookups = append(lookups, clusterName)
+			return nil, apierrors.NewNotFound(apisv1alpha1.Resource("apibindings"), name)
+		},
+		isLogicalClusterDeleting: func(clusterName logicalcluster.Name) bool {
+			return clusterName == "root:deleting"
+		},
+	}
+	defer c.queue.ShutDown()
+
+	for _, key := range []string{"root:deleting|binding", "root:active|binding"} {
+		c.queue.AddRateLimited(key)
+		require.True(t, c.processNextWorkItem(context.Background()))
+		require.Zero(t, c.queue.NumRequeues(key), "key %s must be forgotten", key)
+	}
+	require.Equal(t, []logicalcluster.Name{"root:active"}, lookups)
+}
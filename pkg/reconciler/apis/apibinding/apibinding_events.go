@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"fmt"
+	"time"
+
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilcache "k8s.io/apimachinery/pkg/util/cache"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/tools/record"
+)
+
+// Reasons of the events emitted on APIBindings.
+const (
+	APIExportNotFoundEventReason         = "APIExportNotFound"
+	APIResourceSchemaNotFoundEventReason = "APIResourceSchemaNotFound"
+	BoundResourcesRemovedEventReason     = "BoundResourcesRemoved"
+	CreateCRDFailedEventReason           = "CreateCRDFailed"
+	CRDNotEstablishedEventReason         = "CRDNotEstablished"
+)
+
+// crdEstablishedGracePeriod is the time a bound CRD may take to become established before
+// a warning event is emitted on the APIBindings waiting for it.
+const crdEstablishedGracePeriod = time.Minute
+
+const (
+	// eventClustersCacheSize is the number of objects whose logical cluster is remembered for
+	// routing their events. It matches the cache size of the event correlator.
+	eventClustersCacheSize = 4096
+	// eventClustersTTL is how long the logical cluster of an object is remembered after its
+	// last event. Later updates of the aggregated event are dropped.
+	eventClustersTTL = time.Hour
+)
+
+// clusterAwareEventRecorder records events through a client-go event broadcaster, whose correlator
+// aggregates similar events and filters spam. It remembers the logical cluster of the regarding
+// object, such that the clusterAwareEventSink can create the event there.
+type clusterAwareEventRecorder struct {
+	recorder record.EventRecorder
+	clusters *utilcache.LRUExpireCache
+}
+
+var _ events.EventRecorder = &clusterAwareEventRecorder{}
+
+// newClusterAwareEventRecorder returns a recorder of events on objects in any logical cluster, and the
+// sink the broadcaster has to record to.
+func newClusterAwareEventRecorder(broadcaster record.EventBroadcaster, scheme *runtime.Scheme, kubeClusterClient kcpkubernetesclientset.ClusterInterface) (*clusterAwareEventRecorder, *clusterAwareEventSink) {
+	clusters := utilcache.NewLRUExpireCache(eventClustersCacheSize)
+	recorder := &clusterAwareEventRecorder{
+		recorder: broadcaster.NewRecorder(scheme, corev1.EventSource{Component: ControllerName}),
+		clusters: clusters,
+	}
+	sink := &clusterAwareEventSink{
+		kubeClusterClient: kubeClusterClient,
+		clusters:          clusters,
+	}
+	return recorder, sink
+}
+
+// Eventf records an event on regarding. The related object and the action are not supported by the
+// core/v1 events the broadcaster creates, and are dropped.
+func (r *clusterAwareEventRecorder) Eventf(regarding runtime.Object, related runtime.Object, eventtype, reason, action, note string, args ...interface{}) {
+	obj, ok := regarding.(metav1.Object)
+	if !ok {
+		utilruntime.HandleError(fmt.Errorf("cannot record event for %T without object metadata", regarding))
+		return
+	}
+	r.clusters.Add(obj.GetUID(), logicalcluster.From(obj), eventClustersTTL)
+	r.recorder.Eventf(regarding, eventtype, reason, note, args...)
+}
+
+// clusterAwareEventSink creates events in the logical cluster of the object they are about. Events of
+// cluster-scoped objects end up in the default namespace of that logical cluster.
+type clusterAwareEventSink struct {
+	kubeClusterClient kcpkubernetesclientset.ClusterInterface
+	clusters          *utilcache.LRUExpireCache
+}
+
+var _ record.EventSink = &clusterAwareEventSink{}
+
+func (s *clusterAwareEventSink) Create(event *corev1.Event) (*corev1.Event, error) {
+	sink, err := s.sinkFor(event)
+	if err != nil {
+		return nil, err
+	}
+	return sink.Create(event)
+}
+
+func (s *clusterAwareEventSink) Update(event *corev1.Event) (*corev1.Event, error) {
+	sink, err := s.sinkFor(event)
+	if err != nil {
+		return nil, err
+	}
+	return sink.Update(event)
+}
+
+func (s *clusterAwareEventSink) Patch(event *corev1.Event, data []byte) (*corev1.Event, error) {
+	sink, err := s.sinkFor(event)
+	if err != nil {
+		return nil, err
+	}
+	return sink.Patch(event, data)
+}
+
+func (s *clusterAwareEventSink) sinkFor(event *corev1.Event) (record.EventSink, error) {
+	clusterName, ok := s.clusters.Get(event.InvolvedObject.UID)
+	if !ok {
+		// the broadcaster does not retry request construction errors
+		return nil, &restclient.RequestConstructionError{
+			Err: fmt.Errorf("unknown logical cluster of %s %s", event.InvolvedObject.Kind, event.InvolvedObject.Name),
+		}
+	}
+	return &typedcorev1.EventSinkImpl{
+		Interface: s.kubeClusterClient.Cluster(clusterName.(logicalcluster.Name).Path()).CoreV1().Events(event.Namespace),
+	}, nil
+}
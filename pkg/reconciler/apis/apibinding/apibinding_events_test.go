@@ -0,0 +1,73 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"testing"
+	"time"
+
+	kcpfakekubeclient "github.com/kcp-dev/client-go/kubernetes/fake"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpscheme "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/scheme"
+)
+
+func TestClusterAwareEventRecorder(t *testing.T) {
+	kubeClusterClient := kcpfakekubeclient.NewSimpleClientset()
+	broadcaster := record.NewBroadcaster()
+	defer broadcaster.Shutdown()
+
+	recorder, sink := newClusterAwareEventRecorder(broadcaster, kcpscheme.Scheme, kubeClusterClient)
+	broadcaster.StartRecordingToSink(sink)
+
+	apiBinding := &apisv1alpha1.APIBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "binding",
+			UID:         "binding-uid",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:consumer"},
+		},
+	}
+
+	// identical events are correlated into one event whose count is patched
+	for i := 0; i < 3; i++ {
+		recorder.Eventf(apiBinding, nil, corev1.EventTypeWarning, APIExportNotFoundEventReason, "Binding", "APIExport %s not found", "root:provider|export")
+	}
+
+	require.Eventually(t, func() bool {
+		return len(kubeClusterClient.Actions()) == 3
+	}, wait.ForeverTestTimeout, 100*time.Millisecond)
+	var verbs []string
+	for _, action := range kubeClusterClient.Actions() {
+		require.Equal(t, logicalcluster.NewPath("root:consumer"), action.GetCluster())
+		require.Equal(t, metav1.NamespaceDefault, action.GetNamespace())
+		verbs = append(verbs, action.GetVerb())
+	}
+	require.Equal(t, []string{"create", "patch", "patch"}, verbs)
+
+	// events of objects the recorder has not seen cannot be routed
+	_, err := sink.Create(&corev1.Event{InvolvedObject: corev1.ObjectReference{Kind: "APIBinding", Name: "unknown", UID: "unknown-uid"}})
+	require.Error(t, err)
+	require.IsType(t, &restclient.RequestConstructionError{}, err)
+}
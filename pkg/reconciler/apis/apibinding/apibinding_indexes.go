@@ -21,23 +21,39 @@ import (
 
 	"github.com/kcp-dev/logicalcluster/v3"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/client"
+	"github.com/kcp-dev/kcp/pkg/indexers"
 )
 
-const indexAPIExportsByAPIResourceSchema = "apiExportsByAPIResourceSchema"
+const indexAPIBindingsByBoundGroupResource = "apiBindingsByBoundGroupResource"
 
-// indexAPIExportsByAPIResourceSchemasFunc is an index function that maps an APIExport to its spec.latestResourceSchemas.
-func indexAPIExportsByAPIResourceSchemasFunc(obj interface{}) ([]string, error) {
-	apiExport, ok := obj.(*apisv1alpha1.APIExport)
+// indexAPIBindingsByBoundGroupResourceFunc is an index function that maps an APIBinding to the
+// group resources in its status.boundResources, scoped by the logical cluster of the binding.
+func indexAPIBindingsByBoundGroupResourceFunc(obj interface{}) ([]string, error) {
+	apiBinding, ok := obj.(*apisv1alpha1.APIBinding)
 	if !ok {
-		return []string{}, fmt.Errorf("obj is supposed to be an APIExport, but is %T", obj)
+		return []string{}, fmt.Errorf("obj is supposed to be an APIBinding, but is %T", obj)
 	}
 
-	ret := make([]string, len(apiExport.Spec.LatestResourceSchemas))
-	for i := range apiExport.Spec.LatestResourceSchemas {
-		ret[i] = client.ToClusterAwareKey(logicalcluster.From(apiExport).Path(), apiExport.Spec.LatestResourceSchemas[i])
+	clusterName := logicalcluster.From(apiBinding)
+	ret := make([]string, len(apiBinding.Status.BoundResources))
+	for i, r := range apiBinding.Status.BoundResources {
+		ret[i] = boundGroupResourceKey(clusterName, schema.GroupResource{Group: r.Group, Resource: r.Resource})
 	}
 
 	return ret, nil
 }
+
+func boundGroupResourceKey(clusterName logicalcluster.Name, gr schema.GroupResource) string {
+	return client.ToClusterAwareKey(clusterName.Path(), gr.String())
+}
+
+// getAPIBindingsByBoundGroupResource returns the APIBindings in the given logical cluster that
+// have the given group resource in their bound resources.
+func getAPIBindingsByBoundGroupResource(indexer cache.Indexer, clusterName logicalcluster.Name, gr schema.GroupResource) ([]*apisv1alpha1.APIBinding, error) {
+	return indexers.ByIndex[*apisv1alpha1.APIBinding](indexer, indexAPIBindingsByBoundGroupResource, boundGroupResourceKey(clusterName, gr))
+}
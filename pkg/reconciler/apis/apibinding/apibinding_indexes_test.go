@@ -17,58 +17,94 @@ limitations under the License.
 package apibinding
 
 import (
-	"reflect"
 	"testing"
 
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
 	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
-	"github.com/kcp-dev/kcp/pkg/client"
 )
 
-func TestIndexAPIExportByAPIResourceSchemas(t *testing.T) {
+func TestGetAPIBindingsByBoundGroupResource(t *testing.T) {
+	binding := func(cluster, name string, grs ...schema.GroupResource) *apisv1alpha1.APIBinding {
+		b := &apisv1alpha1.APIBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					logicalcluster.AnnotationKey: cluster,
+				},
+				Name: name,
+			},
+		}
+		for _, gr := range grs {
+			b.Status.BoundResources = append(b.Status.BoundResources, apisv1alpha1.BoundAPIResource{Group: gr.Group, Resource: gr.Resource})
+		}
+		return b
+	}
+
+	widgets := schema.GroupResource{Group: "example.io", Resource: "widgets"}
+	gadgets := schema.GroupResource{Group: "example.io", Resource: "gadgets"}
+	configMaps := schema.GroupResource{Resource: "configmaps"}
+
+	indexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{
+		indexAPIBindingsByBoundGroupResource: indexAPIBindingsByBoundGroupResourceFunc,
+	})
+	for _, b := range []*apisv1alpha1.APIBinding{
+		binding("root:a", "both", widgets, gadgets),
+		binding("root:a", "widgets", widgets),
+		binding("root:a", "core", configMaps),
+		binding("root:b", "widgets", widgets),
+		binding("root:b", "unbound"),
+	} {
+		require.NoError(t, indexer.Add(b))
+	}
+
 	tests := map[string]struct {
-		obj     interface{}
+		cluster logicalcluster.Name
+		gr      schema.GroupResource
 		want    []string
-		wantErr bool
 	}{
-		"not an APIExport": {
-			obj:     "not an APIExport",
-			want:    []string{},
-			wantErr: true,
+		"two bindings in the same cluster": {
+			cluster: "root:a",
+			gr:      widgets,
+			want:    []string{"both", "widgets"},
 		},
-		"valid APIExport": {
-			obj: &apisv1alpha1.APIExport{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{
-						logicalcluster.AnnotationKey: "root:default",
-					},
-					Name: "foo",
-				},
-				Spec: apisv1alpha1.APIExportSpec{
-					LatestResourceSchemas: []string{"schema1", "some-other-schema"},
-				},
-			},
-			want: []string{
-				client.ToClusterAwareKey(logicalcluster.NewPath("root:default"), "schema1"),
-				client.ToClusterAwareKey(logicalcluster.NewPath("root:default"), "some-other-schema"),
-			},
-			wantErr: false,
+		"one binding": {
+			cluster: "root:a",
+			gr:      gadgets,
+			want:    []string{"both"},
+		},
+		"core group": {
+			cluster: "root:a",
+			gr:      configMaps,
+			want:    []string{"core"},
+		},
+		"other cluster": {
+			cluster: "root:b",
+			gr:      widgets,
+			want:    []string{"widgets"},
+		},
+		"not bound": {
+			cluster: "root:b",
+			gr:      gadgets,
 		},
 	}
 
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			got, err := indexAPIExportsByAPIResourceSchemasFunc(tt.obj)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("indexAPIExportsByAPIResourceSchemasFunc() error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("indexAPIExportsByAPIResourceSchemasFunc() got = %v, want %v", got, tt.want)
+			bindings, err := getAPIBindingsByBoundGroupResource(indexer, tt.cluster, tt.gr)
+			require.NoError(t, err)
+
+			var got []string
+			for _, b := range bindings {
+				require.Equal(t, tt.cluster, logicalcluster.From(b))
+				got = append(got, b.Name)
 			}
+			require.ElementsMatch(t, tt.want, got)
 		})
 	}
 }
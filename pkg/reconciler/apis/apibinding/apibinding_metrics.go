@@ -0,0 +1,68 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	compbasemetrics "k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func init() {
+	legacyregistry.MustRegister(boundCRDEstablishedDuration)
+}
+
+var (
+	boundCRDEstablishedDuration = compbasemetrics.NewHistogramVec(
+		&compbasemetrics.HistogramOpts{
+			Name: "apibinding_bound_crd_established_duration_seconds",
+			Help: "Time from creating a bound CRD to observing it Established distribution in seconds",
+			// From .1 to 204.8 seconds
+			Buckets:        prometheus.ExponentialBuckets(.1, 2, 12),
+			StabilityLevel: compbasemetrics.ALPHA,
+		},
+		[]string{"group", "resource"},
+	)
+)
+
+// observeEstablished records how long it took a bound CRD created by this controller to become
+// Established. Every CRD is observed at most once, however many APIBindings use it.
+func (c *controller) observeEstablished(crd *apiextensionsv1.CustomResourceDefinition) {
+	if c.awaitingEstablished == nil || !c.awaitingEstablished.Pop(crd.Name) {
+		return
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, crd.Annotations[apisv1alpha1.AnnotationBoundCRDCreatedAtKey])
+	if err != nil {
+		return
+	}
+
+	boundCRDEstablishedDuration.WithLabelValues(crd.Spec.Group, crd.Spec.Names.Plural).Observe(c.now().Sub(createdAt).Seconds())
+}
+
+func (c *controller) now() time.Time {
+	if c.clock == nil {
+		return time.Now()
+	}
+	return c.clock.Now()
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/component-base/metrics/testutil"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+func TestObserveEstablished(t *testing.T) {
+	boundCRDEstablishedDuration.Reset()
+
+	fakeClock := clocktesting.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	var created *apiextensionsv1.CustomResourceDefinition
+	c := &controller{
+		boundCRDsClusterName: SystemBoundCRDsClusterName,
+		createCRD: func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+			created = crd.DeepCopy()
+			return crd, nil
+		},
+		deletedCRDTracker:   newLockedStringSet(0),
+		awaitingEstablished: newLockedStringSet(0),
+		eventRecorder:       &events.FakeRecorder{},
+		clock:               fakeClock,
+	}
+
+	crd, err := generateCRD(todayWidgetsAPIResourceSchema, SystemBoundCRDsClusterName)
+	require.NoError(t, err)
+
+	// a CRD not created by the controller is not observed
+	c.observeEstablished(crd)
+	count, err := testutil.GetHistogramMetricCount(boundCRDEstablishedDuration.WithLabelValues("kcp.io", "widgets"))
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	r := &bindingReconciler{controller: c}
+	failed, err := r.createCRDs(context.Background(), binding.Build(), []crdToCreate{{schemaName: todayWidgetsAPIResourceSchema.Name, schema: todayWidgetsAPIResourceSchema, crd: crd}})
+	require.NoError(t, err)
+	require.False(t, failed)
+	require.Equal(t, "2023-01-01T00:00:00Z", created.Annotations[apisv1alpha1.AnnotationBoundCRDCreatedAtKey])
+
+	fakeClock.Step(3 * time.Second)
+	c.observeEstablished(created)
+
+	// later reconciles, e.g. of other bindings to the same CRD, are not observed again
+	fakeClock.Step(time.Minute)
+	c.observeEstablished(created)
+
+	count, err = testutil.GetHistogramMetricCount(boundCRDEstablishedDuration.WithLabelValues("kcp.io", "widgets"))
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), count)
+	sum, err := testutil.GetHistogramMetricValue(boundCRDEstablishedDuration.WithLabelValues("kcp.io", "widgets"))
+	require.NoError(t, err)
+	require.Equal(t, 3.0, sum)
+}
+
+func TestObserveEstablishedSkipsAdoptedCRDs(t *testing.T) {
+	crd, err := generateCRD(todayWidgetsAPIResourceSchema, SystemBoundCRDsClusterName)
+	require.NoError(t, err)
+	existing := crd.DeepCopy()
+	delete(existing.Annotations, apisv1alpha1.AnnotationBoundCRDKey)
+
+	var updated *apiextensionsv1.CustomResourceDefinition
+	c := &controller{
+		boundCRDsClusterName: SystemBoundCRDsClusterName,
+		createCRD: func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return nil, apierrors.NewAlreadyExists(apiextensionsv1.Resource("customresourcedefinitions"), crd.Name)
+		},
+		getLiveCRD: func(ctx context.Context, clusterName logicalcluster.Path, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return existing, nil
+		},
+		updateCRD: func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+			updated = crd
+			return crd, nil
+		},
+		deletedCRDTracker:   newLockedStringSet(0),
+		awaitingEstablished: newLockedStringSet(0),
+		eventRecorder:       &events.FakeRecorder{},
+		clock:               clocktesting.NewFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)),
+	}
+
+	r := &bindingReconciler{controller: c}
+	failed, err := r.createCRDs(context.Background(), binding.Build(), []crdToCreate{{schemaName: todayWidgetsAPIResourceSchema.Name, schema: todayWidgetsAPIResourceSchema, crd: crd}})
+	require.NoError(t, err)
+	require.False(t, failed)
+
+	// the creation time of an adopted CRD is unknown, so its establishing is not observed
+	require.False(t, c.awaitingEstablished.Has(crd.Name))
+	require.NotNil(t, updated)
+	require.NotContains(t, updated.Annotations, apisv1alpha1.AnnotationBoundCRDCreatedAtKey)
+}
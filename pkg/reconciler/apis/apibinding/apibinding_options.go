@@ -0,0 +1,81 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/spf13/pflag"
+)
+
+// RemovedSchemaPolicy is what happens to a bound resource whose APIResourceSchema was removed from the APIExport.
+type RemovedSchemaPolicy string
+
+const (
+	// RemovedSchemaPolicyRetain keeps serving the resource and warns about it in the APIBinding status.
+	RemovedSchemaPolicyRetain RemovedSchemaPolicy = "Retain"
+	// RemovedSchemaPolicyDelete unbinds the resource. Its bound CRD, including the objects stored through it, is
+	// deleted by the CRD cleanup controller once no APIBinding binds it anymore.
+	RemovedSchemaPolicyDelete RemovedSchemaPolicy = "Delete"
+)
+
+// DefaultOptions are the default options for the apibinding controller.
+func DefaultOptions() *Options {
+	return &Options{
+		RemovedSchemaPolicy: string(RemovedSchemaPolicyRetain),
+	}
+}
+
+// BindOptions binds the apibinding controller options to the flag set.
+func BindOptions(o *Options, fs *pflag.FlagSet) *Options {
+	fs.StringSliceVar(&o.PropagatedMetadataPrefixes, "apibinding-propagate-metadata-prefixes", o.PropagatedMetadataPrefixes,
+		"Prefixes of the label and annotation keys of APIResourceSchemas that are copied onto their bound CRDs, e.g. example.com/cost-center.")
+	fs.StringVar(&o.RemovedSchemaPolicy, "apibinding-removed-schema-policy", o.RemovedSchemaPolicy,
+		"What happens to bound resources that are removed from their APIExport. Retain keeps serving them with a warning condition, Delete unbinds them, deleting their objects once no APIBinding binds them anymore.")
+	fs.BoolVar(&o.RepairDriftedSubresources, "apibinding-repair-drifted-subresources", o.RepairDriftedSubresources,
+		"Repair the subresources of bound CRDs that drifted from their APIResourceSchema. Bound CRDs are shared by all APIBindings of an APIResourceSchema.")
+	return o
+}
+
+// Options are the options for the apibinding controller.
+type Options struct {
+	PropagatedMetadataPrefixes []string
+	RemovedSchemaPolicy        string
+	RepairDriftedSubresources  bool
+
+	// BoundCRDsClusterName is the logical cluster bound CRDs are created in. SystemBoundCRDsClusterName
+	// is used if empty. It is set by the server, not by a flag.
+	BoundCRDsClusterName logicalcluster.Name
+	// IsLogicalClusterDeleting returns whether a logical cluster is being deleted. A nil func treats
+	// all logical clusters as not deleting. It is set by the server, not by a flag.
+	IsLogicalClusterDeleting func(clusterName logicalcluster.Name) bool
+}
+
+func (o *Options) Validate() error {
+	for _, prefix := range o.PropagatedMetadataPrefixes {
+		if prefix == "" {
+			return fmt.Errorf("--apibinding-propagate-metadata-prefixes must not contain empty prefixes")
+		}
+	}
+	switch RemovedSchemaPolicy(o.RemovedSchemaPolicy) {
+	case "", RemovedSchemaPolicyRetain, RemovedSchemaPolicyDelete:
+	default:
+		return fmt.Errorf("--apibinding-removed-schema-policy must be %s or %s", RemovedSchemaPolicyRetain, RemovedSchemaPolicyDelete)
+	}
+	return nil
+}
@@ -23,16 +23,23 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/kcp-dev/logicalcluster/v3"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apiextensions-apiserver/pkg/apihelpers"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilserrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
@@ -51,6 +58,89 @@ type reconciler interface {
 	reconcile(ctx context.Context, this *apisv1alpha1.APIBinding) (reconcileStatus, error)
 }
 
+// Dependencies are the getters and creators a single APIBinding reconcile step relies on.
+// They mirror the function fields of the controller, such that tools and tests can drive
+// the reconcile logic without informers or a workqueue.
+type Dependencies struct {
+	ListAPIBindings      func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error)
+	GetAPIExport         func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error)
+	GetAPIResourceSchema func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
+	GetAPIConversion     func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIConversion, error)
+
+	CreateCRD func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error)
+	UpdateCRD func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error)
+	GetCRD    func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error)
+	ListCRDs  func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error)
+
+	// GetLiveCRD reads a CRD from the server, bypassing any cache. It is used to read the
+	// latest version of a bound CRD after an update conflict.
+	GetLiveCRD func(ctx context.Context, clusterName logicalcluster.Path, name string) (*apiextensionsv1.CustomResourceDefinition, error)
+
+	// EventRecorder records events on the APIBinding. Events are dropped if nil.
+	EventRecorder events.EventRecorder
+
+	// CRDCreationConcurrency is the number of API groups whose bound CRDs are created in
+	// parallel. The CRDs of one group are always created one after the other, in resource
+	// order. All CRDs are created one after the other if zero.
+	CRDCreationConcurrency int
+
+	// PropagatedMetadataPrefixes are the prefixes of the label and annotation keys of
+	// APIResourceSchemas that are copied onto their bound CRDs.
+	PropagatedMetadataPrefixes []string
+
+	// RemovedSchemaPolicy is what happens to bound resources that were removed from the APIExport.
+	// They are retained if empty.
+	RemovedSchemaPolicy RemovedSchemaPolicy
+
+	// RepairDriftedSubresources is whether the subresources of bound CRDs that drifted from
+	// their APIResourceSchema are repaired.
+	RepairDriftedSubresources bool
+
+	// BoundCRDsClusterName is the logical cluster bound CRDs are created in. It defaults to
+	// SystemBoundCRDsClusterName if empty.
+	BoundCRDsClusterName logicalcluster.Name
+
+	// Clock is used for the time-based decisions of the reconciler. It defaults to the
+	// real clock if nil.
+	Clock clock.PassiveClock
+}
+
+// Reconcile runs a single reconcile step for the given APIBinding using the given
+// dependencies. The APIBinding is mutated in place; committing it is up to the caller.
+// It returns whether the APIBinding should be requeued.
+func Reconcile(ctx context.Context, deps Dependencies, apiBinding *apisv1alpha1.APIBinding) (bool, error) {
+	c := &controller{
+		listAPIBindings:      deps.ListAPIBindings,
+		getAPIExport:         deps.GetAPIExport,
+		getAPIResourceSchema: deps.GetAPIResourceSchema,
+		getAPIConversion:     deps.GetAPIConversion,
+		createCRD:            deps.CreateCRD,
+		updateCRD:            deps.UpdateCRD,
+		getCRD:               deps.GetCRD,
+		listCRDs:             deps.ListCRDs,
+		getLiveCRD:           deps.GetLiveCRD,
+		deletedCRDTracker:    newLockedStringSet(0),
+		clock:                deps.Clock,
+		eventRecorder:        deps.EventRecorder,
+
+		crdCreationConcurrency:     deps.CRDCreationConcurrency,
+		propagatedMetadataPrefixes: deps.PropagatedMetadataPrefixes,
+		removedSchemaPolicy:        deps.RemovedSchemaPolicy,
+		repairDriftedSubresources:  deps.RepairDriftedSubresources,
+		boundCRDsClusterName:       deps.BoundCRDsClusterName,
+	}
+	if c.clock == nil {
+		c.clock = clock.RealClock{}
+	}
+	if c.eventRecorder == nil {
+		c.eventRecorder = &events.FakeRecorder{}
+	}
+	if c.boundCRDsClusterName.Empty() {
+		c.boundCRDsClusterName = SystemBoundCRDsClusterName
+	}
+	return c.reconcile(ctx, apiBinding)
+}
+
 func (c *controller) reconcile(ctx context.Context, apiBinding *apisv1alpha1.APIBinding) (bool, error) {
 	reconcilers := []reconciler{
 		&phaseReconciler{
@@ -169,6 +259,8 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 			apiExportPath,
 			workspaceRef.Name,
 		)
+		r.eventRecorder.Eventf(apiBinding, nil, corev1.EventTypeWarning, APIExportNotFoundEventReason, "Binding",
+			"APIExport %s|%s not found", apiExportPath, workspaceRef.Name)
 		return reconcileStatusContinue, nil
 	}
 	if err != nil {
@@ -190,6 +282,32 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 	// Record the export's permission claims
 	apiBinding.Status.ExportPermissionClaims = apiExport.Spec.PermissionClaims
 
+	// Do not bind before every permission claim of the APIExport is accepted or rejected
+	accepted, unacknowledged := acknowledgePermissionClaims(apiExport, apiBinding)
+	apiBinding.Status.AcceptedPermissionClaims = accepted
+	if len(unacknowledged) > 0 {
+		claims := make([]string, 0, len(unacknowledged))
+		for _, claim := range unacknowledged {
+			claims = append(claims, claim.String())
+		}
+		conditions.MarkFalse(
+			apiBinding,
+			apisv1alpha1.PermissionClaimsAccepted,
+			apisv1alpha1.ClaimsNotAcceptedReason,
+			conditionsv1alpha1.ConditionSeverityWarning,
+			"APIExport %s|%s claims %s, which must be accepted or rejected in spec.permissionClaims",
+			apiExportPath,
+			workspaceRef.Name,
+			strings.Join(claims, ", "),
+		)
+		return reconcileStatusContinue, nil
+	}
+	if len(apiExport.Spec.PermissionClaims) > 0 {
+		conditions.MarkTrue(apiBinding, apisv1alpha1.PermissionClaimsAccepted)
+	} else {
+		conditions.Delete(apiBinding, apisv1alpha1.PermissionClaimsAccepted)
+	}
+
 	// Make sure the APIExport has an identity
 	if apiExport.Status.IdentityHash == "" {
 		conditions.MarkFalse(
@@ -204,13 +322,62 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 		return reconcileStatusContinue, nil
 	}
 
+	// Refuse to bind an APIExport whose identity differs from the one the APIs were bound with. Otherwise,
+	// a re-created APIExport would be served from the storage of the one it replaced.
+	for _, boundResource := range apiBinding.Status.BoundResources {
+		if boundResource.Schema.IdentityHash != "" && boundResource.Schema.IdentityHash != apiExport.Status.IdentityHash {
+			conditions.MarkFalse(
+				apiBinding,
+				apisv1alpha1.APIExportValid,
+				apisv1alpha1.APIExportIdentityMismatchReason,
+				conditionsv1alpha1.ConditionSeverityError,
+				"APIExport %s|%s has identity %q, but %s.%s was bound with identity %q",
+				apiExportPath,
+				workspaceRef.Name,
+				apiExport.Status.IdentityHash,
+				boundResource.Resource,
+				boundResource.Group,
+				boundResource.Schema.IdentityHash,
+			)
+			return reconcileStatusContinue, nil
+		}
+	}
+
+	// Do not bind a revision of the APIExport other than the one the APIBinding is pinned to
+	if pin := apiBinding.Spec.ExportGeneration; pin != 0 {
+		if apiExport.Generation != pin {
+			conditions.MarkFalse(
+				apiBinding,
+				apisv1alpha1.APIExportPinned,
+				apisv1alpha1.APIExportPinOutdatedReason,
+				conditionsv1alpha1.ConditionSeverityWarning,
+				"APIExport %s|%s has generation %d, but the APIBinding is pinned to %d",
+				apiExportPath,
+				workspaceRef.Name,
+				apiExport.Generation,
+				pin,
+			)
+			return reconcileStatusContinue, nil
+		}
+		conditions.MarkTrue(apiBinding, apisv1alpha1.APIExportPinned)
+	} else {
+		conditions.Delete(apiBinding, apisv1alpha1.APIExportPinned)
+	}
+
 	var needToWaitForRequeueWhenEstablished []string
+	var driftedSchemas []string
 
-	// Process all APIResourceSchemas
-	for _, schemaName := range apiExport.Spec.LatestResourceSchemas {
-		bindingClusterName := logicalcluster.From(apiBinding)
+	// notEstablished holds the resources whose bound CRDs are not established after the grace period,
+	// together with the reason reported by the CRD.
+	var notEstablished []string
 
-		// Get the schema
+	// In dry-run mode, CRDs are not created or repaired, but planned in status.
+	dryRun := apiBinding.Annotations[apisv1alpha1.AnnotationDryRunKey] == "true"
+	var dryRunSchemas []string
+
+	// Get all APIResourceSchemas
+	schemas := make([]*apisv1alpha1.APIResourceSchema, 0, len(apiExport.Spec.LatestResourceSchemas))
+	for _, schemaName := range apiExport.Spec.LatestResourceSchemas {
 		schema, err := r.getAPIResourceSchema(logicalcluster.From(apiExport), schemaName)
 		if err != nil {
 			logger.Error(err, "error binding")
@@ -224,14 +391,69 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 			)
 
 			if apierrors.IsNotFound(err) {
+				r.eventRecorder.Eventf(apiBinding, nil, corev1.EventTypeWarning, APIResourceSchemaNotFoundEventReason, "Binding",
+					"APIResourceSchema %s|%s of APIExport %s|%s not found", logicalcluster.From(apiExport), schemaName, apiExportPath, apiExport.Name)
 				return reconcileStatusContinue, nil
 			}
 
 			return reconcileStatusContinue, err
 		}
+		schemas = append(schemas, schema)
+	}
+
+	// Bind in a stable group/resource order such that failures and partial bindings are reproducible.
+	sort.SliceStable(schemas, func(i, j int) bool {
+		if schemas[i].Spec.Group != schemas[j].Spec.Group {
+			return schemas[i].Spec.Group < schemas[j].Spec.Group
+		}
+		return schemas[i].Spec.Names.Plural < schemas[j].Spec.Names.Plural
+	})
+
+	// Refuse to bind if the APIBinding selects resources the APIExport does not export
+	if unexported := unexportedSelectedResources(apiBinding, schemas); len(unexported) > 0 {
+		conditions.MarkFalse(
+			apiBinding,
+			apisv1alpha1.BindingUpToDate,
+			apisv1alpha1.ResourceNotExportedReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"APIExport %s|%s does not export the selected resource(s): %s",
+			apiExportPath,
+			workspaceRef.Name,
+			strings.Join(unexported, ", "),
+		)
+
+		// Only change InitialBindingCompleted if it's false
+		if conditions.IsFalse(apiBinding, apisv1alpha1.InitialBindingCompleted) {
+			conditions.MarkFalse(
+				apiBinding,
+				apisv1alpha1.InitialBindingCompleted,
+				apisv1alpha1.ResourceNotExportedReason,
+				conditionsv1alpha1.ConditionSeverityError,
+				"APIExport %s|%s does not export the selected resource(s): %s",
+				apiExportPath,
+				workspaceRef.Name,
+				strings.Join(unexported, ", "),
+			)
+		}
+
+		return reconcileStatusContinue, nil
+	}
+
+	// Process all APIResourceSchemas
+	schemaBindings := make([]apisv1alpha1.SchemaBindingStatus, 0, len(schemas))
+	var crdsToCreate []crdToCreate
+	for _, schema := range schemas {
+		bindingClusterName := logicalcluster.From(apiBinding)
+		schemaName := schema.Name
 
 		logger := logging.WithObject(logger, schema)
 
+		if !selectsSchema(apiBinding, schema) {
+			logger.V(4).Info("APIResourceSchema not selected, skipping")
+			schemaBindings = append(schemaBindings, newSchemaBindingStatus(schema, apisv1alpha1.SchemaBindingStateSkipped, "Not selected by spec.resourceSelector"))
+			continue
+		}
+
 		// Check for conflicts
 		checker := &conflictChecker{
 			listAPIBindings:      r.listAPIBindings,
@@ -239,6 +461,7 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 			getAPIResourceSchema: r.getAPIResourceSchema,
 			getCRD:               r.getCRD,
 			listCRDs:             r.listCRDs,
+			boundCRDsClusterName: r.boundCRDsClusterName,
 		}
 
 		if err := checker.checkForConflicts(schema, apiBinding); err != nil {
@@ -262,6 +485,10 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 					err,
 				)
 			}
+
+			schemaBindings = append(schemaBindings, newSchemaBindingStatus(schema, apisv1alpha1.SchemaBindingStateConflict, err.Error()))
+			apiBinding.Status.SchemaBindings = schemaBindings
+
 			return reconcileStatusContinue, nil
 		}
 
@@ -289,7 +516,7 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 		}
 
 		// Try to get the bound CRD
-		existingCRD, err := r.getCRD(SystemBoundCRDsClusterName, boundCRDName(schema))
+		existingCRD, err := r.getCRD(r.boundCRDsClusterName, boundCRDName(schema))
 		if err != nil && !apierrors.IsNotFound(err) {
 			conditions.MarkFalse(
 				apiBinding,
@@ -301,7 +528,7 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 
 			return reconcileStatusContinue, fmt.Errorf(
 				"error getting CRD %s|%s for APIBinding %s|%s, APIExport %s|%s, APIResourceSchema %s|%s: %w",
-				SystemBoundCRDsClusterName, boundCRDName(schema),
+				r.boundCRDsClusterName, boundCRDName(schema),
 				bindingClusterName, apiBinding.Name,
 				apiExportPath, apiExport.Name,
 				apiExportPath, schemaName,
@@ -313,16 +540,72 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 			// Bound CRD already exists
 			if !apihelpers.IsCRDConditionTrue(existingCRD, apiextensionsv1.Established) {
 				logger.V(4).Info("CRD is not established", "conditions", fmt.Sprintf("%#v", existingCRD.Status.Conditions))
+				if age := r.now().Sub(existingCRD.CreationTimestamp.Time); age > crdEstablishedGracePeriod {
+					reason := crdNotEstablishedReason(existingCRD)
+					r.eventRecorder.Eventf(apiBinding, nil, corev1.EventTypeWarning, CRDNotEstablishedEventReason, "Binding",
+						"CRD %s|%s for %s.%s is not established after %s: %s", r.boundCRDsClusterName, existingCRD.Name, schema.Spec.Names.Plural, schema.Spec.Group, crdEstablishedGracePeriod, reason)
+					notEstablished = append(notEstablished, fmt.Sprintf("%s: %s", formatGroupResource(schema.Spec.Group, schema.Spec.Names.Plural), reason))
+				} else if r.enqueueAfter != nil {
+					// CRDs that never become established might not change anymore. Look again when the grace period is over.
+					r.enqueueAfter(apiBinding, crdEstablishedGracePeriod-age)
+				}
 				needToWaitForRequeueWhenEstablished = append(needToWaitForRequeueWhenEstablished, schemaName)
+				schemaBindings = append(schemaBindings, newSchemaBindingStatus(schema, apisv1alpha1.SchemaBindingStatePending, "Waiting for the CRD to be established"))
 				continue
 			} else if apihelpers.IsCRDConditionTrue(existingCRD, apiextensionsv1.Terminating) {
 				logger.V(4).Info("CRD is terminating")
 				needToWaitForRequeueWhenEstablished = append(needToWaitForRequeueWhenEstablished, schemaName)
+				schemaBindings = append(schemaBindings, newSchemaBindingStatus(schema, apisv1alpha1.SchemaBindingStatePending, "Waiting for the terminating CRD to be deleted"))
 				continue
 			}
+			r.observeEstablished(existingCRD)
+
+			// Repair the subresources of the bound CRD if they drifted from the schema. The CRD is
+			// shared by all APIBindings of the schema, hence this is opt-in.
+			if drifted := driftedSubresourceVersions(existingCRD, schema); len(drifted) > 0 && !r.repairDriftedSubresources {
+				logger.V(2).Info("bound CRD subresources drifted from APIResourceSchema, not repairing", "versions", drifted)
+			} else if len(drifted) > 0 {
+				logger.Info("bound CRD subresources drifted from APIResourceSchema, repairing", "versions", drifted)
+
+				if dryRun {
+					logger.V(2).Info("dry run, not repairing CRD")
+					dryRunSchemas = append(dryRunSchemas, schemaName)
+					schemaBindings = append(schemaBindings, newSchemaBindingStatus(schema, apisv1alpha1.SchemaBindingStatePending, fmt.Sprintf("Dry run: would repair subresources of versions %s", strings.Join(drifted, ", "))))
+					continue
+				}
+
+				err := r.updateBoundCRD(ctx, existingCRD, func(crd *apiextensionsv1.CustomResourceDefinition) error {
+					// the CRD might have been repaired by someone else in the meantime
+					drifted := sets.NewString(driftedSubresourceVersions(crd, schema)...)
+					for i := range crd.Spec.Versions {
+						if !drifted.Has(crd.Spec.Versions[i].Name) {
+							continue
+						}
+						for _, version := range schema.Spec.Versions {
+							if version.Name == crd.Spec.Versions[i].Name {
+								crd.Spec.Versions[i].Subresources = version.Subresources.DeepCopy()
+							}
+						}
+					}
+					return nil
+				})
+				if err != nil {
+					return reconcileStatusContinue, fmt.Errorf(
+						"error repairing subresources of CRD %s|%s for APIBinding %s|%s: %w",
+						r.boundCRDsClusterName, existingCRD.Name,
+						bindingClusterName, apiBinding.Name,
+						err,
+					)
+				}
+
+				driftedSchemas = append(driftedSchemas, fmt.Sprintf("%s (versions %s)", schemaName, strings.Join(drifted, ", ")))
+			}
 		} else {
 			// Need to create bound CRD
-			crd, err := generateCRD(schema)
+			crd, err := generateCRD(schema, r.boundCRDsClusterName)
+			if err == nil {
+				propagateMetadata(schema, crd, r.propagatedMetadataPrefixes)
+			}
 			if err != nil {
 				logger.Error(err, "error generating CRD")
 
@@ -340,6 +623,13 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 				"groupResource", fmt.Sprintf("%s.%s", crd.Spec.Names.Plural, crd.Spec.Group),
 			)
 
+			if dryRun {
+				logger.V(2).Info("dry run, not creating CRD")
+				dryRunSchemas = append(dryRunSchemas, schemaName)
+				schemaBindings = append(schemaBindings, newSchemaBindingStatus(schema, apisv1alpha1.SchemaBindingStatePending, "Dry run: would create the CRD"))
+				continue
+			}
+
 			// The crd was deleted and needs to be recreated. `existingCRD` might be non-nil if
 			// the lister is behind, so explicitly set to nil to ensure recreation.
 			if r.deletedCRDTracker.Has(crd.Name) {
@@ -347,61 +637,11 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 				existingCRD = nil
 			}
 
-			// Create bound CRD
-			logger.V(2).Info("creating CRD")
-			if _, err := r.createCRD(ctx, SystemBoundCRDsClusterName.Path(), crd); err != nil {
-				schemaClusterName := logicalcluster.From(schema)
-				if apierrors.IsInvalid(err) {
-					status := apierrors.APIStatus(nil)
-					// The error is guaranteed to implement APIStatus here
-					errors.As(err, &status)
-					conditions.MarkFalse(
-						apiBinding,
-						apisv1alpha1.BindingUpToDate,
-						apisv1alpha1.APIResourceSchemaInvalidReason,
-						conditionsv1alpha1.ConditionSeverityError,
-						fmt.Sprintf("APIResourceSchema %s|%s is invalid: %v\"", schemaClusterName, schemaName, status.Status().Details.Causes),
-					)
-					// Only change InitialBindingCompleted if it's false
-					if conditions.IsFalse(apiBinding, apisv1alpha1.InitialBindingCompleted) {
-						conditions.MarkFalse(
-							apiBinding,
-							apisv1alpha1.InitialBindingCompleted,
-							apisv1alpha1.APIResourceSchemaInvalidReason,
-							conditionsv1alpha1.ConditionSeverityError,
-							fmt.Sprintf("APIResourceSchema %s|%s is invalid: %v\"", schemaClusterName, schemaName, status.Status().Details.Causes),
-						)
-					}
-
-					logger.Error(err, "error creating CRD")
-
-					return reconcileStatusContinue, nil
-				}
-
-				conditions.MarkFalse(
-					apiBinding,
-					apisv1alpha1.BindingUpToDate,
-					apisv1alpha1.InternalErrorReason,
-					conditionsv1alpha1.ConditionSeverityError,
-					"An internal error prevented the APIBinding process from completing. Please contact your system administrator for assistance",
-				)
-				// Only change InitialBindingCompleted if it's false
-				if conditions.IsFalse(apiBinding, apisv1alpha1.InitialBindingCompleted) {
-					conditions.MarkFalse(
-						apiBinding,
-						apisv1alpha1.InitialBindingCompleted,
-						apisv1alpha1.InternalErrorReason,
-						conditionsv1alpha1.ConditionSeverityError,
-						"An internal error prevented the APIBinding process from completing. Please contact your system administrator for assistance",
-					)
-				}
-
-				return reconcileStatusContinue, err
-			}
-
-			r.deletedCRDTracker.Remove(crd.Name)
+			// Bound CRDs are created together after all schemas have been looked at.
+			crdsToCreate = append(crdsToCreate, crdToCreate{schemaName: schemaName, schema: schema, crd: crd})
 
 			needToWaitForRequeueWhenEstablished = append(needToWaitForRequeueWhenEstablished, schemaName)
+			schemaBindings = append(schemaBindings, newSchemaBindingStatus(schema, apisv1alpha1.SchemaBindingStatePending, "Waiting for the created CRD to be established"))
 			continue
 		}
 
@@ -444,19 +684,64 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 		if !found {
 			apiBinding.Status.BoundResources = append(apiBinding.Status.BoundResources, newBoundResource)
 		}
+
+		schemaBindings = append(schemaBindings, newSchemaBindingStatus(schema, apisv1alpha1.SchemaBindingStateEstablished, ""))
+	}
+
+	if len(crdsToCreate) > 0 {
+		if failed, err := r.createCRDs(ctx, apiBinding, crdsToCreate); failed {
+			return reconcileStatusContinue, err
+		}
+	}
+
+	apiBinding.Status.SchemaBindings = schemaBindings
+
+	// Handle bound resources the APIExport does not export anymore
+	if removed := removedBoundResources(apiBinding, schemas); len(removed) > 0 {
+		names := make([]string, 0, len(removed))
+		for _, boundResource := range removed {
+			names = append(names, formatGroupResource(boundResource.Group, boundResource.Resource))
+		}
+
+		if r.removedSchemaPolicy == RemovedSchemaPolicyDelete && !dryRun {
+			logger.Info("unbinding resources removed from APIExport", "resources", names)
+			boundResources := make([]apisv1alpha1.BoundAPIResource, 0, len(apiBinding.Status.BoundResources)-len(removed))
+			for _, boundResource := range apiBinding.Status.BoundResources {
+				if exportsResource(schemas, boundResource.Group, boundResource.Resource) {
+					boundResources = append(boundResources, boundResource)
+				}
+			}
+			apiBinding.Status.BoundResources = boundResources
+			r.eventRecorder.Eventf(apiBinding, nil, corev1.EventTypeWarning, BoundResourcesRemovedEventReason, "Binding",
+				"Unbound resource(s) removed from APIExport %s|%s: %s", apiExportPath, apiExport.Name, strings.Join(names, ", "))
+			conditions.Delete(apiBinding, apisv1alpha1.BoundResourcesExported)
+		} else {
+			conditions.MarkFalse(
+				apiBinding,
+				apisv1alpha1.BoundResourcesExported,
+				apisv1alpha1.SchemaRemovedReason,
+				conditionsv1alpha1.ConditionSeverityWarning,
+				"APIExport %s|%s does not export the bound resource(s) %s anymore. They are still served",
+				apiExportPath,
+				apiExport.Name,
+				strings.Join(names, ", "),
+			)
+		}
+	} else {
+		conditions.Delete(apiBinding, apisv1alpha1.BoundResourcesExported)
 	}
 
 	conditions.MarkTrue(apiBinding, apisv1alpha1.APIExportValid)
 
-	if len(needToWaitForRequeueWhenEstablished) > 0 {
-		sort.Strings(needToWaitForRequeueWhenEstablished)
+	if len(dryRunSchemas) > 0 {
+		sort.Strings(dryRunSchemas)
 
 		conditions.MarkFalse(
 			apiBinding,
 			apisv1alpha1.BindingUpToDate,
-			apisv1alpha1.WaitingForEstablishedReason,
+			apisv1alpha1.DryRunReason,
 			conditionsv1alpha1.ConditionSeverityInfo,
-			"Waiting for API(s) to be established: %s", strings.Join(needToWaitForRequeueWhenEstablished, ", "),
+			"Dry run: API(s) not bound: %s", strings.Join(dryRunSchemas, ", "),
 		)
 
 		// Only change InitialBindingCompleted if it's false
@@ -464,11 +749,65 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 			conditions.MarkFalse(
 				apiBinding,
 				apisv1alpha1.InitialBindingCompleted,
-				apisv1alpha1.WaitingForEstablishedReason,
+				apisv1alpha1.DryRunReason,
 				conditionsv1alpha1.ConditionSeverityInfo,
-				"Waiting for API(s) to be established: %s", strings.Join(needToWaitForRequeueWhenEstablished, ", "),
+				"Dry run: API(s) not bound: %s", strings.Join(dryRunSchemas, ", "),
+			)
+		}
+
+		return reconcileStatusContinue, nil
+	}
+
+	if len(needToWaitForRequeueWhenEstablished) > 0 {
+		sort.Strings(needToWaitForRequeueWhenEstablished)
+
+		reason := apisv1alpha1.WaitingForEstablishedReason
+		severity := conditionsv1alpha1.ConditionSeverityInfo
+		message := fmt.Sprintf("Waiting for API(s) to be established: %s", strings.Join(needToWaitForRequeueWhenEstablished, ", "))
+		if len(notEstablished) > 0 {
+			reason = apisv1alpha1.CRDNotEstablishedReason
+			severity = conditionsv1alpha1.ConditionSeverityError
+			message = fmt.Sprintf("API(s) not established after %s: %s", crdEstablishedGracePeriod, strings.Join(notEstablished, "; "))
+		}
+
+		conditions.MarkFalse(
+			apiBinding,
+			apisv1alpha1.BindingUpToDate,
+			reason,
+			severity,
+			"%s", message,
+		)
+
+		// Only change InitialBindingCompleted if it's false
+		if conditions.IsFalse(apiBinding, apisv1alpha1.InitialBindingCompleted) {
+			conditions.MarkFalse(
+				apiBinding,
+				apisv1alpha1.InitialBindingCompleted,
+				reason,
+				severity,
+				"%s", message,
 			)
 		}
+
+		return reconcileStatusContinue, nil
+	}
+
+	// The spec is only observed once binding completed. Until then, the status is stale.
+	apiBinding.Status.ObservedGeneration = apiBinding.Generation
+
+	// Record the APIExport revision the binding is now bound to.
+	apiBinding.Status.APIExportGeneration = apiExport.Generation
+
+	if len(driftedSchemas) > 0 {
+		conditions.MarkTrue(apiBinding, apisv1alpha1.InitialBindingCompleted)
+		conditions.MarkFalse(
+			apiBinding,
+			apisv1alpha1.BindingUpToDate,
+			apisv1alpha1.BoundCRDDriftedReason,
+			conditionsv1alpha1.ConditionSeverityWarning,
+			"Repairing drifted subresources of bound API(s): %s", strings.Join(driftedSchemas, "; "),
+		)
+		apiBinding.Status.Phase = apisv1alpha1.APIBindingPhaseBound
 	} else {
 		conditions.MarkTrue(apiBinding, apisv1alpha1.InitialBindingCompleted)
 		conditions.MarkTrue(apiBinding, apisv1alpha1.BindingUpToDate)
@@ -478,16 +817,359 @@ func (r *bindingReconciler) reconcile(ctx context.Context, apiBinding *apisv1alp
 	return reconcileStatusContinue, nil
 }
 
+// crdToCreate is a bound CRD that does not exist yet, together with the schema it is generated from.
+type crdToCreate struct {
+	schemaName string
+	schema     *apisv1alpha1.APIResourceSchema
+	crd        *apiextensionsv1.CustomResourceDefinition
+}
+
+// createCRDs creates the given bound CRDs, which are sorted by group and resource. The CRDs of up to
+// crdCreationConcurrency API groups are created in parallel, the CRDs of one group one after the
+// other in resource order. Creation is attempted for every CRD, even if some fail. CRDs that turn
+// out to exist already are adopted if possible. It returns whether any creation failed, and the
+// aggregated errors worth retrying. Invalid schemas are only recorded in the conditions, as
+// retrying will not fix them.
+func (r *bindingReconciler) createCRDs(ctx context.Context, apiBinding *apisv1alpha1.APIBinding, crds []crdToCreate) (bool, error) {
+	logger := klog.FromContext(ctx)
+
+	workers := r.crdCreationConcurrency
+	if workers < 1 {
+		workers = 1
+	}
+
+	// groups holds the index of the first CRD of every API group, followed by len(crds).
+	var groups []int
+	for i, c := range crds {
+		if i == 0 || c.crd.Spec.Group != crds[i-1].crd.Spec.Group {
+			groups = append(groups, i)
+		}
+	}
+	groups = append(groups, len(crds))
+
+	createdAt := r.now().UTC().Format(time.RFC3339Nano)
+	createErrs := make([]error, len(crds))
+	adopted := make([]bool, len(crds))
+	workqueue.ParallelizeUntil(ctx, workers, len(groups)-1, func(g int) {
+		for i := groups[g]; i < groups[g+1]; i++ {
+			logger := logging.WithObject(logger, crds[i].crd).WithValues(
+				"groupResource", fmt.Sprintf("%s.%s", crds[i].crd.Spec.Names.Plural, crds[i].crd.Spec.Group),
+			)
+			logger.V(2).Info("creating CRD")
+			crd := crds[i].crd.DeepCopy()
+			crd.Annotations[apisv1alpha1.AnnotationBoundCRDCreatedAtKey] = createdAt
+			_, createErrs[i] = r.createCRD(ctx, r.boundCRDsClusterName.Path(), crd)
+			if apierrors.IsAlreadyExists(createErrs[i]) {
+				logger.V(2).Info("CRD already exists, adopting it")
+				createErrs[i] = r.adoptCRD(ctx, crds[i])
+				adopted[i] = true
+			}
+		}
+	})
+
+	var errs []error
+	var invalid []string
+	for i, c := range crds {
+		err := createErrs[i]
+		if err == nil {
+			r.deletedCRDTracker.Remove(c.crd.Name)
+			// Adopted CRDs were not created now, so their time to become established is unknown.
+			if r.awaitingEstablished != nil && !adopted[i] {
+				r.awaitingEstablished.Add(c.crd.Name)
+			}
+			continue
+		}
+
+		r.eventRecorder.Eventf(apiBinding, nil, corev1.EventTypeWarning, CreateCRDFailedEventReason, "Binding",
+			"Failed to create CRD for %s.%s: %v", c.crd.Spec.Names.Plural, c.crd.Spec.Group, err)
+
+		if !apierrors.IsInvalid(err) {
+			errs = append(errs, err)
+			continue
+		}
+		logging.WithObject(logger, c.crd).Error(err, "error creating CRD")
+
+		schemaClusterName := logicalcluster.From(c.schema)
+		status := apierrors.APIStatus(nil)
+		// The error is guaranteed to implement APIStatus here
+		errors.As(err, &status)
+		invalid = append(invalid, fmt.Sprintf("APIResourceSchema %s|%s is invalid: %v", schemaClusterName, c.schemaName, status.Status().Details.Causes))
+	}
+
+	if len(invalid) > 0 {
+		conditions.MarkFalse(
+			apiBinding,
+			apisv1alpha1.BindingUpToDate,
+			apisv1alpha1.APIResourceSchemaInvalidReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"%s", strings.Join(invalid, "; "),
+		)
+		// Only change InitialBindingCompleted if it's false
+		if conditions.IsFalse(apiBinding, apisv1alpha1.InitialBindingCompleted) {
+			conditions.MarkFalse(
+				apiBinding,
+				apisv1alpha1.InitialBindingCompleted,
+				apisv1alpha1.APIResourceSchemaInvalidReason,
+				conditionsv1alpha1.ConditionSeverityError,
+				"%s", strings.Join(invalid, "; "),
+			)
+		}
+	}
+
+	if len(errs) > 0 {
+		conditions.MarkFalse(
+			apiBinding,
+			apisv1alpha1.BindingUpToDate,
+			apisv1alpha1.InternalErrorReason,
+			conditionsv1alpha1.ConditionSeverityError,
+			"An internal error prevented the APIBinding process from completing. Please contact your system administrator for assistance",
+		)
+		// Only change InitialBindingCompleted if it's false
+		if conditions.IsFalse(apiBinding, apisv1alpha1.InitialBindingCompleted) {
+			conditions.MarkFalse(
+				apiBinding,
+				apisv1alpha1.InitialBindingCompleted,
+				apisv1alpha1.InternalErrorReason,
+				conditionsv1alpha1.ConditionSeverityError,
+				"An internal error prevented the APIBinding process from completing. Please contact your system administrator for assistance",
+			)
+		}
+	}
+
+	return len(invalid) > 0 || len(errs) > 0, utilserrors.NewAggregate(errs)
+}
+
+// adoptCRD takes over a bound CRD that exists although it was not found before creating it, e.g.
+// because it was left over from a crash or the informer cache lags behind. Only a CRD generated
+// from the same APIResourceSchema is adopted. As APIResourceSchemas are immutable, its spec is the
+// one that would have been created; only the annotations owned by this controller are set.
+func (r *bindingReconciler) adoptCRD(ctx context.Context, c crdToCreate) error {
+	// the informer cache has not seen the CRD yet, so read it from the server
+	existing, err := r.getLiveCRD(ctx, r.boundCRDsClusterName.Path(), c.crd.Name)
+	if err != nil {
+		return fmt.Errorf("failed to get existing CRD %s|%s: %w", r.boundCRDsClusterName, c.crd.Name, err)
+	}
+
+	return r.updateBoundCRD(ctx, existing, func(crd *apiextensionsv1.CustomResourceDefinition) error {
+		if crd.Annotations[apisv1alpha1.AnnotationSchemaClusterKey] != c.crd.Annotations[apisv1alpha1.AnnotationSchemaClusterKey] ||
+			crd.Annotations[apisv1alpha1.AnnotationSchemaNameKey] != c.crd.Annotations[apisv1alpha1.AnnotationSchemaNameKey] {
+			return fmt.Errorf("CRD %s|%s already exists for APIResourceSchema %s|%s, cannot adopt it for APIResourceSchema %s|%s",
+				r.boundCRDsClusterName, crd.Name,
+				crd.Annotations[apisv1alpha1.AnnotationSchemaClusterKey], crd.Annotations[apisv1alpha1.AnnotationSchemaNameKey],
+				c.crd.Annotations[apisv1alpha1.AnnotationSchemaClusterKey], c.crd.Annotations[apisv1alpha1.AnnotationSchemaNameKey],
+			)
+		}
+
+		if crd.Annotations == nil {
+			crd.Annotations = map[string]string{}
+		}
+		crd.Annotations[apisv1alpha1.AnnotationBoundCRDKey] = ""
+		return nil
+	})
+}
+
+// updateBoundCRD applies mutate to a copy of the given bound CRD and updates it, unless mutate changed
+// nothing. The update is conditional on the resourceVersion of the CRD mutate was applied to. On a
+// conflict, e.g. with the apiextensions-apiserver updating the CRD at the same time, the CRD is read
+// again from the server, as the informer cache likely lags behind, and mutate re-applied, a bounded
+// number of times, instead of failing the whole reconcile.
+func (r *bindingReconciler) updateBoundCRD(ctx context.Context, crd *apiextensionsv1.CustomResourceDefinition, mutate func(crd *apiextensionsv1.CustomResourceDefinition) error) error {
+	logger := klog.FromContext(ctx)
+
+	attempt := 0
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if attempt > 0 {
+			logger.V(4).Info("conflict updating bound CRD, retrying", "crd", crd.Name, "attempt", attempt)
+			latest, err := r.getLiveCRD(ctx, r.boundCRDsClusterName.Path(), crd.Name)
+			if err != nil {
+				return err
+			}
+			crd = latest
+		}
+		attempt++
+
+		updated := crd.DeepCopy()
+		if err := mutate(updated); err != nil {
+			return err
+		}
+		if equality.Semantic.DeepEqual(crd, updated) {
+			return nil
+		}
+
+		_, err := r.updateCRD(ctx, r.boundCRDsClusterName.Path(), updated)
+		return err
+	})
+}
+
+// propagateMetadata copies the labels and annotations of the schema whose keys start with one of
+// the given prefixes onto the bound CRD. Keys already set on the CRD are never overwritten.
+func propagateMetadata(schema *apisv1alpha1.APIResourceSchema, crd *apiextensionsv1.CustomResourceDefinition, prefixes []string) {
+	propagate := func(from map[string]string, to *map[string]string) {
+		for k, v := range from {
+			if _, found := (*to)[k]; found || !hasAnyPrefix(k, prefixes) {
+				continue
+			}
+			if *to == nil {
+				*to = map[string]string{}
+			}
+			(*to)[k] = v
+		}
+	}
+	propagate(schema.Labels, &crd.Labels)
+	propagate(schema.Annotations, &crd.Annotations)
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectsSchema returns whether the APIBinding binds the given schema of its APIExport. That is the case
+// if the APIBinding has no resource selector, if the selector selects the resource of the schema, or if the
+// resource is bound already.
+func selectsSchema(apiBinding *apisv1alpha1.APIBinding, schema *apisv1alpha1.APIResourceSchema) bool {
+	if len(apiBinding.Spec.ResourceSelector) == 0 {
+		return true
+	}
+	for _, gr := range apiBinding.Spec.ResourceSelector {
+		if gr.Group == schema.Spec.Group && gr.Resource == schema.Spec.Names.Plural {
+			return true
+		}
+	}
+	for _, boundResource := range apiBinding.Status.BoundResources {
+		if boundResource.Group == schema.Spec.Group && boundResource.Resource == schema.Spec.Names.Plural {
+			return true
+		}
+	}
+	return false
+}
+
+// unexportedSelectedResources returns the resources selected by the APIBinding that are not among the
+// given schemas of its APIExport, formatted as resource.group.
+func unexportedSelectedResources(apiBinding *apisv1alpha1.APIBinding, schemas []*apisv1alpha1.APIResourceSchema) []string {
+	var unexported []string
+	for _, gr := range apiBinding.Spec.ResourceSelector {
+		if !exportsResource(schemas, gr.Group, gr.Resource) {
+			unexported = append(unexported, formatGroupResource(gr.Group, gr.Resource))
+		}
+	}
+	return unexported
+}
+
+// crdNotEstablishedReason returns why a CRD is not established, preferring the conditions that point
+// at a problem of its schema or names over the Established condition itself.
+func crdNotEstablishedReason(crd *apiextensionsv1.CustomResourceDefinition) string {
+	if cond := apihelpers.FindCRDCondition(crd, apiextensionsv1.NonStructuralSchema); cond != nil && cond.Status == apiextensionsv1.ConditionTrue {
+		return fmt.Sprintf("%s: %s", cond.Reason, cond.Message)
+	}
+	if cond := apihelpers.FindCRDCondition(crd, apiextensionsv1.NamesAccepted); cond != nil && cond.Status == apiextensionsv1.ConditionFalse {
+		return fmt.Sprintf("%s: %s", cond.Reason, cond.Message)
+	}
+	if cond := apihelpers.FindCRDCondition(crd, apiextensionsv1.Established); cond != nil && cond.Message != "" {
+		return fmt.Sprintf("%s: %s", cond.Reason, cond.Message)
+	}
+	return "no reason reported"
+}
+
+// removedBoundResources returns the bound resources of the APIBinding that are not among the given
+// schemas of its APIExport.
+func removedBoundResources(apiBinding *apisv1alpha1.APIBinding, schemas []*apisv1alpha1.APIResourceSchema) []apisv1alpha1.BoundAPIResource {
+	var removed []apisv1alpha1.BoundAPIResource
+	for _, boundResource := range apiBinding.Status.BoundResources {
+		if !exportsResource(schemas, boundResource.Group, boundResource.Resource) {
+			removed = append(removed, boundResource)
+		}
+	}
+	return removed
+}
+
+func exportsResource(schemas []*apisv1alpha1.APIResourceSchema, group, resource string) bool {
+	for _, schema := range schemas {
+		if group == schema.Spec.Group && resource == schema.Spec.Names.Plural {
+			return true
+		}
+	}
+	return false
+}
+
+// formatGroupResource formats a group and resource as resource.group, or resource for the core group.
+func formatGroupResource(group, resource string) string {
+	if group == "" {
+		return resource
+	}
+	return resource + "." + group
+}
+
+// acknowledgePermissionClaims splits the permission claims of the APIExport into the ones accepted
+// in spec.permissionClaims of the APIBinding and the ones neither accepted nor rejected there.
+// Rejected claims are in neither list.
+func acknowledgePermissionClaims(apiExport *apisv1alpha1.APIExport, apiBinding *apisv1alpha1.APIBinding) (accepted, unacknowledged []apisv1alpha1.PermissionClaim) {
+	for _, exportClaim := range apiExport.Spec.PermissionClaims {
+		var state apisv1alpha1.AcceptablePermissionClaimState
+		for _, bindingClaim := range apiBinding.Spec.PermissionClaims {
+			if bindingClaim.Equal(exportClaim) {
+				state = bindingClaim.State
+				break
+			}
+		}
+
+		switch state {
+		case apisv1alpha1.ClaimAccepted:
+			accepted = append(accepted, exportClaim)
+		case apisv1alpha1.ClaimRejected:
+		default:
+			unacknowledged = append(unacknowledged, exportClaim)
+		}
+	}
+	return accepted, unacknowledged
+}
+
+// driftedSubresourceVersions returns the names of the versions of the bound CRD whose subresources
+// differ from the ones declared by the same version in the APIResourceSchema.
+func driftedSubresourceVersions(crd *apiextensionsv1.CustomResourceDefinition, schema *apisv1alpha1.APIResourceSchema) []string {
+	var drifted []string
+	for _, crdVersion := range crd.Spec.Versions {
+		for _, schemaVersion := range schema.Spec.Versions {
+			if schemaVersion.Name != crdVersion.Name {
+				continue
+			}
+			var existing apiextensionsv1.CustomResourceSubresources
+			if crdVersion.Subresources != nil {
+				existing = *crdVersion.Subresources
+			}
+			if !equality.Semantic.DeepEqual(existing, schemaVersion.Subresources) {
+				drifted = append(drifted, crdVersion.Name)
+			}
+		}
+	}
+	return drifted
+}
+
+func newSchemaBindingStatus(schema *apisv1alpha1.APIResourceSchema, state apisv1alpha1.SchemaBindingState, message string) apisv1alpha1.SchemaBindingStatus {
+	return apisv1alpha1.SchemaBindingStatus{
+		Group:    schema.Spec.Group,
+		Resource: schema.Spec.Names.Plural,
+		Schema:   schema.Name,
+		CRDName:  boundCRDName(schema),
+		State:    state,
+		Message:  message,
+	}
+}
+
 func boundCRDName(schema *apisv1alpha1.APIResourceSchema) string {
 	return string(schema.UID)
 }
 
-func generateCRD(schema *apisv1alpha1.APIResourceSchema) (*apiextensionsv1.CustomResourceDefinition, error) {
+func generateCRD(schema *apisv1alpha1.APIResourceSchema, clusterName logicalcluster.Name) (*apiextensionsv1.CustomResourceDefinition, error) {
 	crd := &apiextensionsv1.CustomResourceDefinition{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: boundCRDName(schema),
 			Annotations: map[string]string{
-				logicalcluster.AnnotationKey:            SystemBoundCRDsClusterName.String(),
+				logicalcluster.AnnotationKey:            clusterName.String(),
 				apisv1alpha1.AnnotationBoundCRDKey:      "",
 				apisv1alpha1.AnnotationSchemaClusterKey: logicalcluster.From(schema).String(),
 				apisv1alpha1.AnnotationSchemaNameKey:    schema.Name,
@@ -497,6 +1179,11 @@ func generateCRD(schema *apisv1alpha1.APIResourceSchema) (*apiextensionsv1.Custo
 			Group: schema.Spec.Group,
 			Names: schema.Spec.Names,
 			Scope: schema.Spec.Scope,
+			// Conversion between versions is done by kcp from the APIConversion of the schema, if any.
+			// Webhook conversion is not supported for bound CRDs.
+			Conversion: &apiextensionsv1.CustomResourceConversion{
+				Strategy: apiextensionsv1.NoneConverter,
+			},
 		},
 	}
 
@@ -516,7 +1203,7 @@ func generateCRD(schema *apisv1alpha1.APIResourceSchema) (*apiextensionsv1.Custo
 			Storage:                  version.Storage,
 			Deprecated:               version.Deprecated,
 			DeprecationWarning:       version.DeprecationWarning,
-			Subresources:             &version.Subresources,
+			Subresources:             version.Subresources.DeepCopy(),
 			AdditionalPrinterColumns: version.AdditionalPrinterColumns,
 		}
 
@@ -19,8 +19,14 @@ package apibinding
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
 	"github.com/kcp-dev/logicalcluster/v3"
 	"github.com/stretchr/testify/require"
 
@@ -31,12 +37,19 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/events"
+	clocktesting "k8s.io/utils/clock/testing"
 	"k8s.io/utils/pointer"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/core"
 	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
+	"github.com/kcp-dev/kcp/pkg/indexers"
 )
 
 // requireConditionMatches looks for a condition matching c in g. Only fields that are set in c are compared (Type is
@@ -196,6 +209,7 @@ func TestReconcileBinding(t *testing.T) {
 		wantAPIExportNotFound                   bool
 		wantAPIExportInternalError              bool
 		wantWaitingForEstablished               bool
+		wantCRDNotEstablished                   bool
 		wantAPIExportValid                      bool
 		wantReady                               bool
 		wantNoReady                             bool
@@ -207,7 +221,15 @@ func TestReconcileBinding(t *testing.T) {
 		wantBoundResources                      []apisv1alpha1.BoundAPIResource
 		wantNamingConflict                      bool
 		crdEstablished                          bool
+		crdAge                                  time.Duration
 		crdStorageVersions                      []string
+		crdSubresources                         *apiextensionsv1.CustomResourceSubresources
+		repairDriftedSubresources               bool
+		wantBoundCRDDrifted                     bool
+		wantEventReasons                        []string
+		wantSchemaBindingStates                 []apisv1alpha1.SchemaBindingState
+		wantIdentityMismatch                    bool
+		wantDryRun                              bool
 	}{
 		"Update to nil workspace ref reports invalid APIExport": {
 			apiBinding:           binding.DeepCopy().WithoutWorkspaceReference().Build(),
@@ -217,6 +239,7 @@ func TestReconcileBinding(t *testing.T) {
 			apiBinding:            binding.Build(),
 			getAPIExportError:     apierrors.NewNotFound(apisv1alpha1.SchemeGroupVersion.WithResource("apiexports").GroupResource(), "some-export"),
 			wantAPIExportNotFound: true,
+			wantEventReasons:      []string{APIExportNotFoundEventReason},
 		},
 		"APIExport get error - random error": {
 			apiBinding:                 binding.Build(),
@@ -229,6 +252,7 @@ func TestReconcileBinding(t *testing.T) {
 			getAPIResourceSchemaError:  apierrors.NewNotFound(schema.GroupResource{}, "foo"),
 			wantAPIExportInternalError: true,
 			wantError:                  false,
+			wantEventReasons:           []string{APIResourceSchemaNotFoundEventReason},
 		},
 		"APIResourceSchema get error - random error": {
 			apiBinding:                 binding.Build(),
@@ -258,6 +282,7 @@ func TestReconcileBinding(t *testing.T) {
 			createCRDError:                          apierrors.NewInvalid(apiextensionsv1.Kind("CustomResourceDefinition"), "todaywidgetsuid", field.ErrorList{field.Forbidden(field.NewPath("foo"), "details")}),
 			wantInitialBindingCompleteSchemaInvalid: true,
 			wantError:                               false,
+			wantEventReasons:                        []string{CreateCRDFailedEventReason},
 		},
 		"create CRD fails - other error": {
 			apiBinding:                              binding.Build(),
@@ -265,6 +290,7 @@ func TestReconcileBinding(t *testing.T) {
 			createCRDError:                          errors.New("foo"),
 			wantInitialBindingCompleteInternalError: true,
 			wantError:                               true,
+			wantEventReasons:                        []string{CreateCRDFailedEventReason},
 		},
 		"create CRD - no other bindings": {
 			apiBinding:                binding.Build(),
@@ -273,6 +299,16 @@ func TestReconcileBinding(t *testing.T) {
 			wantAPIExportValid:        true,
 			wantBoundAPIExport:        true,
 			wantBoundResources:        nil, // not yet established
+			wantSchemaBindingStates:   []apisv1alpha1.SchemaBindingState{apisv1alpha1.SchemaBindingStatePending},
+		},
+		"create CRD - dry run": {
+			apiBinding:              binding.DeepCopy().WithAnnotation(apisv1alpha1.AnnotationDryRunKey, "true").Build(),
+			wantCreateCRD:           false,
+			wantDryRun:              true,
+			wantAPIExportValid:      true,
+			wantBoundAPIExport:      true,
+			wantBoundResources:      nil, // not created
+			wantSchemaBindingStates: []apisv1alpha1.SchemaBindingState{apisv1alpha1.SchemaBindingStatePending},
 		},
 		"create CRD - other bindings - no conflicts": {
 			apiBinding: binding.Build(),
@@ -290,7 +326,8 @@ func TestReconcileBinding(t *testing.T) {
 			existingAPIBindings: []*apisv1alpha1.APIBinding{
 				conflicting.Build(),
 			},
-			wantNamingConflict: true,
+			wantNamingConflict:      true,
+			wantSchemaBindingStates: []apisv1alpha1.SchemaBindingState{apisv1alpha1.SchemaBindingStateConflict},
 		},
 		"bind existing CRD - other bindings - conflicts": {
 			apiBinding: binding.Build(),
@@ -311,6 +348,22 @@ func TestReconcileBinding(t *testing.T) {
 			wantBoundAPIExport:        true,
 			wantBoundResources:        nil, // not established yet
 			wantWaitingForEstablished: true,
+			wantSchemaBindingStates:   []apisv1alpha1.SchemaBindingState{apisv1alpha1.SchemaBindingStatePending},
+		},
+		"CRD already exists but isn't established after the grace period": {
+			apiBinding:              binding.Build(),
+			getCRDError:             nil,
+			crdExists:               true,
+			crdEstablished:          false,
+			crdAge:                  2 * crdEstablishedGracePeriod,
+			crdStorageVersions:      []string{"v0", "v1"},
+			wantAPIExportValid:      true,
+			wantReady:               false,
+			wantBoundAPIExport:      true,
+			wantBoundResources:      nil, // not established yet
+			wantCRDNotEstablished:   true,
+			wantEventReasons:        []string{CRDNotEstablishedEventReason},
+			wantSchemaBindingStates: []apisv1alpha1.SchemaBindingState{apisv1alpha1.SchemaBindingStatePending},
 		},
 		"CRD already exists and is established": {
 			apiBinding:         binding.Build(),
@@ -335,6 +388,126 @@ func TestReconcileBinding(t *testing.T) {
 			},
 			wantPhaseBound:             true,
 			wantInitialBindingComplete: true,
+			wantSchemaBindingStates:    []apisv1alpha1.SchemaBindingState{apisv1alpha1.SchemaBindingStateEstablished},
+		},
+		"CRD is established but its subresources drifted": {
+			apiBinding:         binding.Build(),
+			crdExists:          true,
+			crdEstablished:     true,
+			crdStorageVersions: []string{"v1"},
+			crdSubresources: &apiextensionsv1.CustomResourceSubresources{
+				Scale: &apiextensionsv1.CustomResourceSubresourceScale{
+					SpecReplicasPath:   ".spec.replicas",
+					StatusReplicasPath: ".status.replicas",
+				},
+			},
+			repairDriftedSubresources: true,
+			wantUpdateCRD:             true,
+			wantBoundCRDDrifted:       true,
+			wantAPIExportValid:        true,
+			wantReady:                 true,
+			wantBoundAPIExport:        true,
+			wantBoundResources: []apisv1alpha1.BoundAPIResource{
+				{
+					Group:    "kcp.io",
+					Resource: "widgets",
+					Schema: apisv1alpha1.BoundAPIResourceSchema{
+						Name:         "today.widgets.kcp.io",
+						UID:          "todaywidgetsuid",
+						IdentityHash: "hash1",
+					},
+					StorageVersions: []string{"v1"},
+				},
+			},
+			wantPhaseBound:             true,
+			wantInitialBindingComplete: true,
+		},
+		"CRD subresources drifted and repair disabled": {
+			apiBinding:         binding.Build(),
+			crdExists:          true,
+			crdEstablished:     true,
+			crdStorageVersions: []string{"v1"},
+			crdSubresources: &apiextensionsv1.CustomResourceSubresources{
+				Scale: &apiextensionsv1.CustomResourceSubresourceScale{
+					SpecReplicasPath:   ".spec.replicas",
+					StatusReplicasPath: ".status.replicas",
+				},
+			},
+			wantUpdateCRD:      false,
+			wantAPIExportValid: true,
+			wantReady:          true,
+			wantBoundAPIExport: true,
+			wantBoundResources: []apisv1alpha1.BoundAPIResource{
+				{
+					Group:    "kcp.io",
+					Resource: "widgets",
+					Schema: apisv1alpha1.BoundAPIResourceSchema{
+						Name:         "today.widgets.kcp.io",
+						UID:          "todaywidgetsuid",
+						IdentityHash: "hash1",
+					},
+					StorageVersions: []string{"v1"},
+				},
+			},
+			wantPhaseBound:             true,
+			wantInitialBindingComplete: true,
+			wantSchemaBindingStates:    []apisv1alpha1.SchemaBindingState{apisv1alpha1.SchemaBindingStateEstablished},
+		},
+		"CRD subresources drifted - dry run": {
+			apiBinding:         binding.DeepCopy().WithAnnotation(apisv1alpha1.AnnotationDryRunKey, "true").Build(),
+			crdExists:          true,
+			crdEstablished:     true,
+			crdStorageVersions: []string{"v1"},
+			crdSubresources: &apiextensionsv1.CustomResourceSubresources{
+				Scale: &apiextensionsv1.CustomResourceSubresourceScale{
+					SpecReplicasPath:   ".spec.replicas",
+					StatusReplicasPath: ".status.replicas",
+				},
+			},
+			repairDriftedSubresources: true,
+			wantUpdateCRD:             false,
+			wantDryRun:                true,
+			wantAPIExportValid:        true,
+			wantBoundAPIExport:        true,
+			wantSchemaBindingStates:   []apisv1alpha1.SchemaBindingState{apisv1alpha1.SchemaBindingStatePending},
+		},
+		"CRD subresources drifted and repair fails": {
+			apiBinding:         binding.Build(),
+			crdExists:          true,
+			crdEstablished:     true,
+			crdStorageVersions: []string{"v1"},
+			crdSubresources: &apiextensionsv1.CustomResourceSubresources{
+				Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+			},
+			repairDriftedSubresources: true,
+			wantUpdateCRD:             true,
+			updateCRDError:            errors.New("foo"),
+			wantError:                 true,
+		},
+		"APIExport identity differs from the bound identity": {
+			apiBinding: binding.DeepCopy().
+				WithBoundResources(
+					new(boundAPIResourceBuilder).
+						WithGroupResource("kcp.io", "widgets").
+						WithSchema("today.widgets.kcp.io", "todaywidgetsuid").
+						WithIdentityHash("recreated-export-hash").
+						BoundAPIResource,
+				).
+				Build(),
+			crdExists:      true,
+			crdEstablished: true,
+			wantBoundResources: []apisv1alpha1.BoundAPIResource{
+				{
+					Group:    "kcp.io",
+					Resource: "widgets",
+					Schema: apisv1alpha1.BoundAPIResourceSchema{
+						Name:         "today.widgets.kcp.io",
+						UID:          "todaywidgetsuid",
+						IdentityHash: "recreated-export-hash",
+					},
+				},
+			},
+			wantIdentityMismatch: true,
 		},
 		"Ensure merging storage versions works": {
 			apiBinding:         rebinding.Build(),
@@ -365,6 +538,8 @@ func TestReconcileBinding(t *testing.T) {
 	for testName, tc := range tests {
 		t.Run(testName, func(t *testing.T) {
 			createCRDCalled := false
+			updateCRDCalled := false
+			recorder := events.NewFakeRecorder(10)
 
 			apiExports := map[string]*apisv1alpha1.APIExport{
 				"some-export": {
@@ -439,6 +614,8 @@ func TestReconcileBinding(t *testing.T) {
 			}
 
 			c := &controller{
+				boundCRDsClusterName:      SystemBoundCRDsClusterName,
+				repairDriftedSubresources: tc.repairDriftedSubresources,
 				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
 					return tc.existingAPIBindings, nil
 				},
@@ -468,6 +645,9 @@ func TestReconcileBinding(t *testing.T) {
 					}
 
 					crd := &apiextensionsv1.CustomResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{
+							CreationTimestamp: metav1.NewTime(time.Now().Add(-tc.crdAge)),
+						},
 						Status: apiextensionsv1.CustomResourceDefinitionStatus{
 							StoredVersions: tc.crdStorageVersions,
 						},
@@ -488,6 +668,12 @@ func TestReconcileBinding(t *testing.T) {
 						return nil, apierrors.NewNotFound(schema.GroupResource{}, "")
 					}
 
+					if tc.crdSubresources != nil {
+						crd.Spec.Versions = []apiextensionsv1.CustomResourceDefinitionVersion{
+							{Name: "v1", Subresources: tc.crdSubresources},
+						}
+					}
+
 					if tc.crdEstablished {
 						crd.Status.Conditions = append(crd.Status.Conditions, apiextensionsv1.CustomResourceDefinitionCondition{
 							Type:   apiextensionsv1.Established,
@@ -504,7 +690,14 @@ func TestReconcileBinding(t *testing.T) {
 					createCRDCalled = true
 					return crd, tc.createCRDError
 				},
+				updateCRD: func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+					updateCRDCalled = true
+					require.Len(t, crd.Spec.Versions, 1)
+					require.Equal(t, &todayWidgetsAPIResourceSchema.Spec.Versions[0].Subresources, crd.Spec.Versions[0].Subresources)
+					return crd, tc.updateCRDError
+				},
 				deletedCRDTracker: &lockedStringSet{},
+				eventRecorder:     recorder,
 			}
 
 			requeue, err := c.reconcile(context.Background(), tc.apiBinding)
@@ -517,6 +710,22 @@ func TestReconcileBinding(t *testing.T) {
 
 			require.Equal(t, tc.wantRequeue, requeue, "mismatched requeue, want: %v, got: %v", tc.wantRequeue, requeue)
 			require.Equal(t, tc.wantCreateCRD, createCRDCalled, "mismatch on CRD creation expectation")
+			require.Equal(t, tc.wantUpdateCRD, updateCRDCalled, "mismatch on CRD update expectation")
+
+			var gotEventReasons []string
+			for len(recorder.Events) > 0 {
+				gotEventReasons = append(gotEventReasons, strings.Fields(<-recorder.Events)[1])
+			}
+			require.Equal(t, tc.wantEventReasons, gotEventReasons, "mismatch on recorded events")
+
+			if tc.wantSchemaBindingStates != nil {
+				var gotStates []apisv1alpha1.SchemaBindingState
+				for _, sb := range tc.apiBinding.Status.SchemaBindings {
+					require.Equal(t, "todaywidgetsuid", sb.CRDName, "unexpected bound CRD name")
+					gotStates = append(gotStates, sb.State)
+				}
+				require.Equal(t, tc.wantSchemaBindingStates, gotStates, "mismatch on schema binding states")
+			}
 
 			if tc.wantInvalidReference {
 				requireConditionMatches(t, tc.apiBinding, &conditionsv1alpha1.Condition{
@@ -536,6 +745,15 @@ func TestReconcileBinding(t *testing.T) {
 				})
 			}
 
+			if tc.wantIdentityMismatch {
+				requireConditionMatches(t, tc.apiBinding, &conditionsv1alpha1.Condition{
+					Type:     apisv1alpha1.APIExportValid,
+					Status:   corev1.ConditionFalse,
+					Severity: conditionsv1alpha1.ConditionSeverityError,
+					Reason:   apisv1alpha1.APIExportIdentityMismatchReason,
+				})
+			}
+
 			if tc.wantAPIExportInternalError {
 				requireConditionMatches(t, tc.apiBinding, &conditionsv1alpha1.Condition{
 					Type:     apisv1alpha1.APIExportValid,
@@ -554,6 +772,15 @@ func TestReconcileBinding(t *testing.T) {
 				})
 			}
 
+			if tc.wantCRDNotEstablished {
+				requireConditionMatches(t, tc.apiBinding, &conditionsv1alpha1.Condition{
+					Type:     apisv1alpha1.InitialBindingCompleted,
+					Status:   corev1.ConditionFalse,
+					Severity: conditionsv1alpha1.ConditionSeverityError,
+					Reason:   apisv1alpha1.CRDNotEstablishedReason,
+				})
+			}
+
 			if tc.wantAPIExportValid {
 				requireConditionMatches(t, tc.apiBinding, conditions.TrueCondition(apisv1alpha1.APIExportValid))
 			}
@@ -604,6 +831,26 @@ func TestReconcileBinding(t *testing.T) {
 				})
 			}
 
+			if tc.wantDryRun {
+				requireConditionMatches(t, tc.apiBinding, &conditionsv1alpha1.Condition{
+					Type:     apisv1alpha1.InitialBindingCompleted,
+					Status:   corev1.ConditionFalse,
+					Severity: conditionsv1alpha1.ConditionSeverityInfo,
+					Reason:   apisv1alpha1.DryRunReason,
+					Message:  "Dry run: API(s) not bound: today.widgets.kcp.io",
+				})
+			}
+
+			if tc.wantBoundCRDDrifted {
+				requireConditionMatches(t, tc.apiBinding, &conditionsv1alpha1.Condition{
+					Type:     apisv1alpha1.BindingUpToDate,
+					Status:   corev1.ConditionFalse,
+					Severity: conditionsv1alpha1.ConditionSeverityWarning,
+					Reason:   apisv1alpha1.BoundCRDDriftedReason,
+					Message:  "today.widgets.kcp.io (versions v1)",
+				})
+			}
+
 			if tc.wantInitialBindingCompleteInternalError {
 				requireConditionMatches(t, tc.apiBinding, &conditionsv1alpha1.Condition{
 					Type:     apisv1alpha1.InitialBindingCompleted,
@@ -625,114 +872,1332 @@ func TestReconcileBinding(t *testing.T) {
 	}
 }
 
-func TestCRDFromAPIResourceSchema(t *testing.T) {
-	tests := map[string]struct {
-		schema  *apisv1alpha1.APIResourceSchema
-		want    *apiextensionsv1.CustomResourceDefinition
-		wantErr bool
-	}{
-		"full schema": {
-			schema: &apisv1alpha1.APIResourceSchema{
-				ObjectMeta: metav1.ObjectMeta{
-					Annotations: map[string]string{
-						logicalcluster.AnnotationKey: "my-cluster",
+// newTestDependencies returns Dependencies serving the given APIExport with the
+// todayWidgetsAPIResourceSchema, no other APIBindings and established bound CRDs.
+// Tests override the fields they need.
+func newTestDependencies(export *apisv1alpha1.APIExport) Dependencies {
+	return Dependencies{
+		ListAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+			return nil, nil
+		},
+		GetAPIExport: func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+			return export, nil
+		},
+		GetAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+			return todayWidgetsAPIResourceSchema, nil
+		},
+		GetCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: name},
+				Status: apiextensionsv1.CustomResourceDefinitionStatus{
+					Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+						{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
 					},
-					Name: "my-name",
-					UID:  types.UID("my-uuid"),
 				},
-				Spec: apisv1alpha1.APIResourceSchemaSpec{
-					Group: "my-group",
-					Names: apiextensionsv1.CustomResourceDefinitionNames{
-						Plural:     "widgets",
-						Singular:   "widget",
-						ShortNames: []string{"w"},
-						Kind:       "Widget",
-						ListKind:   "WidgetList",
-						Categories: []string{"things"},
-					},
-					Scope: apiextensionsv1.NamespaceScoped,
-					Versions: []apisv1alpha1.APIResourceVersion{
-						{
-							Name:               "v1",
-							Served:             true,
-							Storage:            false,
-							Deprecated:         true,
-							DeprecationWarning: pointer.StringPtr("deprecated!"),
-							Schema: runtime.RawExtension{
-								Raw: []byte(`
-{
-	"description": "foo",
-	"type": "object"
+			}, nil
+		},
+		ListCRDs: func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+			return nil, nil
+		},
+	}
 }
-								`),
-							},
-							Subresources: apiextensionsv1.CustomResourceSubresources{
-								Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
-								Scale: &apiextensionsv1.CustomResourceSubresourceScale{
-									SpecReplicasPath:   ".spec.replicas",
-									StatusReplicasPath: ".status.replicas",
-									LabelSelectorPath:  pointer.StringPtr(".status.selector"),
-								},
-							},
-							AdditionalPrinterColumns: []apiextensionsv1.CustomResourceColumnDefinition{
-								{
-									Name:        "My Column",
-									Type:        "string",
-									Format:      "string",
-									Description: "This is my column",
-									Priority:    1,
-									JSONPath:    ".spec.myColumn",
-								},
-							},
-						},
-						{
-							Name:       "v2",
-							Served:     true,
-							Storage:    true,
-							Deprecated: false,
-							Schema: runtime.RawExtension{
-								Raw: []byte(`
-{
-	"description": "foo",
-	"type": "object"
+
+// getCRDNotFound is a Dependencies.GetCRD for bound CRDs that do not exist yet.
+func getCRDNotFound(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+	return nil, apierrors.NewNotFound(schema.GroupResource{}, name)
 }
-								`),
-							},
-							Subresources: apiextensionsv1.CustomResourceSubresources{
-								Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
-								Scale: &apiextensionsv1.CustomResourceSubresourceScale{
-									SpecReplicasPath:   ".spec.replicas",
-									StatusReplicasPath: ".status.replicas",
-									LabelSelectorPath:  pointer.StringPtr(".status.selector"),
-								},
-							},
-							AdditionalPrinterColumns: []apiextensionsv1.CustomResourceColumnDefinition{
-								{
-									Name:        "My Column",
-									Type:        "string",
-									Format:      "string",
-									Description: "This is my column",
-									Priority:    1,
-									JSONPath:    ".spec.myColumn",
-								},
-							},
-						},
-					},
-				},
+
+func TestReconcileWithDependencies(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "org-some-workspace",
 			},
-			want: &apiextensionsv1.CustomResourceDefinition{
-				ObjectMeta: metav1.ObjectMeta{
-					Name: "my-uuid",
-					Annotations: map[string]string{
-						logicalcluster.AnnotationKey:            SystemBoundCRDsClusterName.String(),
-						apisv1alpha1.AnnotationBoundCRDKey:      "",
-						apisv1alpha1.AnnotationSchemaClusterKey: "my-cluster",
-						apisv1alpha1.AnnotationSchemaNameKey:    "my-name",
-					},
+			Name: "some-export",
+		},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.kcp.io"},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash1"},
+	}
+
+	var created []*apiextensionsv1.CustomResourceDefinition
+	deps := newTestDependencies(export)
+	deps.GetCRD = getCRDNotFound
+	deps.CreateCRD = func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+		require.Equal(t, SystemBoundCRDsClusterName.Path(), clusterName)
+		created = append(created, crd)
+		return crd, nil
+	}
+
+	apiBinding := binding.Build()
+	requeue, err := Reconcile(context.Background(), deps, apiBinding)
+	require.NoError(t, err)
+	require.False(t, requeue)
+
+	require.Len(t, created, 1)
+	require.Equal(t, "todaywidgetsuid", created[0].Name)
+	requireConditionMatches(t, apiBinding, &conditionsv1alpha1.Condition{
+		Type:   apisv1alpha1.InitialBindingCompleted,
+		Status: corev1.ConditionFalse,
+		Reason: apisv1alpha1.WaitingForEstablishedReason,
+	})
+}
+
+func TestReconcileCustomBoundCRDsCluster(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "org-some-workspace",
+			},
+			Name: "some-export",
+		},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.kcp.io"},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash1"},
+	}
+	boundCRDsClusterName := logicalcluster.Name("system:test-bound-crds")
+
+	var created []*apiextensionsv1.CustomResourceDefinition
+	deps := newTestDependencies(export)
+	deps.GetCRD = func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+		require.Equal(t, boundCRDsClusterName, clusterName)
+		return nil, apierrors.NewNotFound(schema.GroupResource{}, name)
+	}
+	deps.CreateCRD = func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+		require.Equal(t, boundCRDsClusterName.Path(), clusterName)
+		created = append(created, crd)
+		return crd, nil
+	}
+	deps.BoundCRDsClusterName = boundCRDsClusterName
+
+	_, err := Reconcile(context.Background(), deps, binding.Build())
+	require.NoError(t, err)
+
+	require.Len(t, created, 1)
+	require.Equal(t, boundCRDsClusterName, logicalcluster.From(created[0]))
+}
+
+func TestReconcileObservedGeneration(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "org-some-workspace",
+			},
+			Name: "some-export",
+		},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.kcp.io"},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash1"},
+	}
+	var getAPIExportErr error
+	var otherBindings []*apisv1alpha1.APIBinding
+	var established apiextensionsv1.ConditionStatus
+
+	deps := newTestDependencies(export)
+	deps.ListAPIBindings = func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+		return otherBindings, nil
+	}
+	deps.GetAPIExport = func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+		if getAPIExportErr != nil {
+			return nil, getAPIExportErr
+		}
+		return export, nil
+	}
+	deps.GetCRD = func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{
+				Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+					{Type: apiextensionsv1.Established, Status: established},
 				},
-				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
-					Group: "my-group",
-					Names: apiextensionsv1.CustomResourceDefinitionNames{
+			},
+		}, nil
+	}
+
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	apiBinding := binding.DeepCopy().WithPhase(apisv1alpha1.APIBindingPhaseBinding).WithCreationTimestamp(created).Build()
+	apiBinding.Generation = 1
+	olderBinding := binding.DeepCopy().WithName("older").WithPhase(apisv1alpha1.APIBindingPhaseBinding).WithCreationTimestamp(created.Add(-time.Hour)).Build()
+	conditions.MarkFalse(olderBinding, apisv1alpha1.InitialBindingCompleted, apisv1alpha1.WaitingForEstablishedReason, conditionsv1alpha1.ConditionSeverityInfo, "")
+
+	steps := []struct {
+		name                   string
+		generation             int64
+		getAPIExportErr        error
+		otherBindings          []*apisv1alpha1.APIBinding
+		notEstablished         bool
+		wantErr                bool
+		wantObservedGeneration int64
+	}{
+		{name: "naming conflict with an older binding", generation: 1, otherBindings: []*apisv1alpha1.APIBinding{olderBinding}},
+		{name: "CRD not established", generation: 1, notEstablished: true},
+		{name: "initial bind", generation: 1, wantObservedGeneration: 1},
+		{name: "spec change fails", generation: 2, getAPIExportErr: errors.New("boom"), wantErr: true, wantObservedGeneration: 1},
+		{name: "still failing", generation: 3, getAPIExportErr: errors.New("boom"), wantErr: true, wantObservedGeneration: 1},
+		{name: "catches up", generation: 3, wantObservedGeneration: 3},
+	}
+	for _, step := range steps {
+		apiBinding.Generation = step.generation
+		getAPIExportErr = step.getAPIExportErr
+		otherBindings = step.otherBindings
+		established = apiextensionsv1.ConditionTrue
+		if step.notEstablished {
+			established = apiextensionsv1.ConditionFalse
+		}
+
+		_, err := Reconcile(context.Background(), deps, apiBinding)
+		if step.wantErr {
+			require.Error(t, err, step.name)
+		} else {
+			require.NoError(t, err, step.name)
+		}
+		require.Equal(t, step.wantObservedGeneration, apiBinding.Status.ObservedGeneration, step.name)
+	}
+}
+
+func TestReconcileWithClock(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "org-some-workspace",
+			},
+			Name: "some-export",
+		},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.kcp.io"},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash1"},
+	}
+
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakeClock(created)
+	recorder := events.NewFakeRecorder(10)
+
+	var crd *apiextensionsv1.CustomResourceDefinition
+	deps := newTestDependencies(export)
+	deps.GetCRD = func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+		if crd == nil {
+			return nil, apierrors.NewNotFound(schema.GroupResource{}, name)
+		}
+		return crd, nil
+	}
+	deps.CreateCRD = func(ctx context.Context, clusterName logicalcluster.Path, newCRD *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+		crd = newCRD.DeepCopy()
+		crd.CreationTimestamp = metav1.NewTime(clock.Now())
+		return crd, nil
+	}
+	deps.EventRecorder = recorder
+	deps.Clock = clock
+
+	_, err := Reconcile(context.Background(), deps, binding.Build())
+	require.NoError(t, err)
+	require.NotNil(t, crd)
+	require.Equal(t, created.Format(time.RFC3339Nano), crd.Annotations[apisv1alpha1.AnnotationBoundCRDCreatedAtKey])
+
+	steps := []struct {
+		name       string
+		step       time.Duration
+		wantReason string
+		wantEvents int
+	}{
+		{name: "within the grace period", step: crdEstablishedGracePeriod / 2, wantReason: apisv1alpha1.WaitingForEstablishedReason},
+		{name: "at the end of the grace period", step: crdEstablishedGracePeriod / 2, wantReason: apisv1alpha1.WaitingForEstablishedReason},
+		{name: "after the grace period", step: time.Second, wantReason: apisv1alpha1.CRDNotEstablishedReason, wantEvents: 1},
+	}
+	for _, step := range steps {
+		clock.Step(step.step)
+
+		apiBinding := binding.Build()
+		apiBinding.Status.Phase = apisv1alpha1.APIBindingPhaseBinding
+		_, err := Reconcile(context.Background(), deps, apiBinding)
+		require.NoError(t, err, step.name)
+		requireConditionMatches(t, apiBinding, &conditionsv1alpha1.Condition{
+			Type:   apisv1alpha1.BindingUpToDate,
+			Status: corev1.ConditionFalse,
+			Reason: step.wantReason,
+		})
+
+		require.Len(t, recorder.Events, step.wantEvents, step.name)
+		for len(recorder.Events) > 0 {
+			require.Contains(t, <-recorder.Events, CRDNotEstablishedEventReason, step.name)
+		}
+	}
+}
+
+func TestReconcileCRDNeverEstablished(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "org-some-workspace",
+			},
+			Name: "some-export",
+		},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.kcp.io"},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash1"},
+	}
+
+	created := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := clocktesting.NewFakeClock(created.Add(10 * time.Second))
+	crd := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "todaywidgetsuid",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{
+			Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+				{Type: apiextensionsv1.NamesAccepted, Status: apiextensionsv1.ConditionTrue},
+				{Type: apiextensionsv1.NonStructuralSchema, Status: apiextensionsv1.ConditionTrue, Reason: "Violations", Message: "spec.versions[0].schema.openAPIV3Schema.type: Required value: must not be empty at the root"},
+				{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionFalse, Reason: "Installing", Message: "the initial names have been accepted"},
+			},
+		},
+	}
+
+	var enqueuedAfter []time.Duration
+	c := &controller{
+		listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+			return nil, nil
+		},
+		getAPIExport: func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+			return export, nil
+		},
+		getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+			return todayWidgetsAPIResourceSchema, nil
+		},
+		getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return crd, nil
+		},
+		listCRDs: func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+			return nil, nil
+		},
+		enqueueAfter: func(apiBinding *apisv1alpha1.APIBinding, duration time.Duration) {
+			enqueuedAfter = append(enqueuedAfter, duration)
+		},
+		deletedCRDTracker: newLockedStringSet(0),
+		eventRecorder:     events.NewFakeRecorder(10),
+		clock:             clock,
+	}
+
+	// within the grace period, the binding waits and looks again when it is over
+	apiBinding := binding.DeepCopy().WithPhase(apisv1alpha1.APIBindingPhaseBinding).Build()
+	requeue, err := c.reconcile(context.Background(), apiBinding)
+	require.NoError(t, err)
+	require.False(t, requeue)
+	require.Equal(t, []time.Duration{crdEstablishedGracePeriod - 10*time.Second}, enqueuedAfter)
+	requireConditionMatches(t, apiBinding, &conditionsv1alpha1.Condition{
+		Type:     apisv1alpha1.InitialBindingCompleted,
+		Status:   corev1.ConditionFalse,
+		Severity: conditionsv1alpha1.ConditionSeverityInfo,
+		Reason:   apisv1alpha1.WaitingForEstablishedReason,
+	})
+
+	// after the grace period, the reason of the CRD is reported, and the binding is not requeued anymore
+	enqueuedAfter = nil
+	clock.SetTime(created.Add(crdEstablishedGracePeriod + time.Second))
+	requeue, err = c.reconcile(context.Background(), apiBinding)
+	require.NoError(t, err)
+	require.False(t, requeue)
+	require.Empty(t, enqueuedAfter)
+	for _, conditionType := range []conditionsv1alpha1.ConditionType{apisv1alpha1.InitialBindingCompleted, apisv1alpha1.BindingUpToDate} {
+		requireConditionMatches(t, apiBinding, &conditionsv1alpha1.Condition{
+			Type:     conditionType,
+			Status:   corev1.ConditionFalse,
+			Severity: conditionsv1alpha1.ConditionSeverityError,
+			Reason:   apisv1alpha1.CRDNotEstablishedReason,
+			Message:  "widgets.kcp.io: Violations: spec.versions[0].schema.openAPIV3Schema.type: Required value",
+		})
+	}
+}
+
+func TestReconcileRecordsBoundAPIExportRevision(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "org-some-workspace",
+			},
+			Name:       "some-export",
+			Generation: 1,
+		},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.kcp.io"},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash1"},
+	}
+	established := false
+
+	deps := newTestDependencies(export)
+	deps.GetCRD = func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+		crd := &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if established {
+			crd.Status.Conditions = []apiextensionsv1.CustomResourceDefinitionCondition{
+				{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+			}
+		}
+		return crd, nil
+	}
+
+	apiBinding := binding.Build()
+
+	// not bound yet while the CRD is not established
+	_, err := Reconcile(context.Background(), deps, apiBinding)
+	require.NoError(t, err)
+	require.Zero(t, apiBinding.Status.APIExportGeneration)
+
+	established = true
+	_, err = Reconcile(context.Background(), deps, apiBinding)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), apiBinding.Status.APIExportGeneration)
+
+	// the export changes, and the binding is rebound to it
+	export.Generation = 2
+	_, err = Reconcile(context.Background(), deps, apiBinding)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), apiBinding.Status.APIExportGeneration)
+}
+
+func TestReconcilePinnedAPIExport(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "org-some-workspace",
+			},
+			Name:            "some-export",
+			ResourceVersion: "10",
+			Generation:      3,
+		},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.kcp.io"},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash1"},
+	}
+
+	deps := newTestDependencies(export)
+
+	// the cache server assigns its own resourceVersion to the copy of the APIExport
+	cached := export.DeepCopy()
+	cached.ResourceVersion = "1234"
+	cached.Annotations[core.LogicalClusterPathAnnotationKey] = "org:some-workspace"
+	global := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{
+		indexers.ByLogicalClusterPathAndName: indexers.IndexByLogicalClusterPathAndName,
+	})
+	require.NoError(t, global.Add(cached))
+	local := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{
+		indexers.ByLogicalClusterPathAndName: indexers.IndexByLogicalClusterPathAndName,
+	})
+
+	tests := map[string]struct {
+		pin           int64
+		fromCache     bool
+		wantBound     bool
+		wantCondition *conditionsv1alpha1.Condition
+	}{
+		"no pin": {
+			wantBound: true,
+		},
+		"matching pin": {
+			pin:           3,
+			wantBound:     true,
+			wantCondition: conditions.TrueCondition(apisv1alpha1.APIExportPinned),
+		},
+		"matching pin, APIExport from the cache server": {
+			pin:           3,
+			fromCache:     true,
+			wantBound:     true,
+			wantCondition: conditions.TrueCondition(apisv1alpha1.APIExportPinned),
+		},
+		"outdated pin": {
+			pin: 2,
+			wantCondition: &conditionsv1alpha1.Condition{
+				Type:     apisv1alpha1.APIExportPinned,
+				Status:   corev1.ConditionFalse,
+				Severity: conditionsv1alpha1.ConditionSeverityWarning,
+				Reason:   apisv1alpha1.APIExportPinOutdatedReason,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			apiBinding := binding.Build()
+			apiBinding.Spec.ExportGeneration = tc.pin
+
+			deps := deps
+			if tc.fromCache {
+				deps.GetAPIExport = getAPIExportFromIndexers(local, global)
+			}
+
+			_, err := Reconcile(context.Background(), deps, apiBinding)
+			require.NoError(t, err)
+
+			if tc.wantBound {
+				require.Equal(t, int64(3), apiBinding.Status.APIExportGeneration)
+				require.Len(t, apiBinding.Status.BoundResources, 1)
+			} else {
+				require.Zero(t, apiBinding.Status.APIExportGeneration)
+				require.Empty(t, apiBinding.Status.BoundResources)
+			}
+
+			if tc.wantCondition == nil {
+				require.False(t, conditions.Has(apiBinding, apisv1alpha1.APIExportPinned), "unexpected APIExportPinned condition")
+			} else {
+				requireConditionMatches(t, apiBinding, tc.wantCondition)
+			}
+		})
+	}
+}
+
+func TestReconcilePermissionClaims(t *testing.T) {
+	secrets := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "secrets"}, All: true}
+	configMaps := apisv1alpha1.PermissionClaim{GroupResource: apisv1alpha1.GroupResource{Resource: "configmaps"}, All: true}
+
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "org-some-workspace",
+			},
+			Name:            "some-export",
+			ResourceVersion: "10",
+		},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.kcp.io"},
+			PermissionClaims:      []apisv1alpha1.PermissionClaim{secrets, configMaps},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash1"},
+	}
+
+	deps := newTestDependencies(export)
+
+	acceptable := func(claim apisv1alpha1.PermissionClaim, state apisv1alpha1.AcceptablePermissionClaimState) apisv1alpha1.AcceptablePermissionClaim {
+		return apisv1alpha1.AcceptablePermissionClaim{PermissionClaim: claim, State: state}
+	}
+
+	tests := map[string]struct {
+		claims        []apisv1alpha1.AcceptablePermissionClaim
+		wantBound     bool
+		wantAccepted  []apisv1alpha1.PermissionClaim
+		wantCondition *conditionsv1alpha1.Condition
+	}{
+		"all claims accepted": {
+			claims: []apisv1alpha1.AcceptablePermissionClaim{
+				acceptable(configMaps, apisv1alpha1.ClaimAccepted),
+				acceptable(secrets, apisv1alpha1.ClaimAccepted),
+			},
+			wantBound:     true,
+			wantAccepted:  []apisv1alpha1.PermissionClaim{secrets, configMaps},
+			wantCondition: conditions.TrueCondition(apisv1alpha1.PermissionClaimsAccepted),
+		},
+		"claims accepted and rejected": {
+			claims: []apisv1alpha1.AcceptablePermissionClaim{
+				acceptable(secrets, apisv1alpha1.ClaimRejected),
+				acceptable(configMaps, apisv1alpha1.ClaimAccepted),
+			},
+			wantBound:     true,
+			wantAccepted:  []apisv1alpha1.PermissionClaim{configMaps},
+			wantCondition: conditions.TrueCondition(apisv1alpha1.PermissionClaimsAccepted),
+		},
+		"no claims accepted": {
+			wantCondition: &conditionsv1alpha1.Condition{
+				Type:     apisv1alpha1.PermissionClaimsAccepted,
+				Status:   corev1.ConditionFalse,
+				Severity: conditionsv1alpha1.ConditionSeverityWarning,
+				Reason:   apisv1alpha1.ClaimsNotAcceptedReason,
+			},
+		},
+		"claims partially accepted": {
+			claims: []apisv1alpha1.AcceptablePermissionClaim{
+				acceptable(secrets, apisv1alpha1.ClaimAccepted),
+			},
+			wantAccepted: []apisv1alpha1.PermissionClaim{secrets},
+			wantCondition: &conditionsv1alpha1.Condition{
+				Type:     apisv1alpha1.PermissionClaimsAccepted,
+				Status:   corev1.ConditionFalse,
+				Severity: conditionsv1alpha1.ConditionSeverityWarning,
+				Reason:   apisv1alpha1.ClaimsNotAcceptedReason,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			apiBinding := binding.Build()
+			apiBinding.Spec.PermissionClaims = tc.claims
+
+			_, err := Reconcile(context.Background(), deps, apiBinding)
+			require.NoError(t, err)
+
+			if tc.wantBound {
+				require.Len(t, apiBinding.Status.BoundResources, 1)
+			} else {
+				require.Empty(t, apiBinding.Status.BoundResources)
+			}
+			require.Equal(t, export.Spec.PermissionClaims, apiBinding.Status.ExportPermissionClaims)
+			require.Equal(t, tc.wantAccepted, apiBinding.Status.AcceptedPermissionClaims)
+			requireConditionMatches(t, apiBinding, tc.wantCondition)
+		})
+	}
+}
+
+func TestReconcileResourceSelector(t *testing.T) {
+	todayGadgetsAPIResourceSchema := todayWidgetsAPIResourceSchema.DeepCopy()
+	todayGadgetsAPIResourceSchema.Name = "today.gadgets.kcp.io"
+	todayGadgetsAPIResourceSchema.UID = "todaygadgetsuid"
+	todayGadgetsAPIResourceSchema.Spec.Names = apiextensionsv1.CustomResourceDefinitionNames{
+		Plural:   "gadgets",
+		Singular: "gadget",
+		Kind:     "Gadget",
+		ListKind: "GadgetList",
+	}
+	schemas := map[string]*apisv1alpha1.APIResourceSchema{
+		todayWidgetsAPIResourceSchema.Name: todayWidgetsAPIResourceSchema,
+		todayGadgetsAPIResourceSchema.Name: todayGadgetsAPIResourceSchema,
+	}
+
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "org-some-workspace",
+			},
+			Name: "some-export",
+		},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.kcp.io", "today.gadgets.kcp.io"},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash1"},
+	}
+
+	deps := newTestDependencies(export)
+	deps.GetAPIResourceSchema = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+		return schemas[name], nil
+	}
+
+	tests := map[string]struct {
+		apiBinding         *apisv1alpha1.APIBinding
+		selector           []apisv1alpha1.GroupResource
+		wantBoundResources []string
+		wantSchemaStates   map[string]apisv1alpha1.SchemaBindingState
+		wantCondition      *conditionsv1alpha1.Condition
+	}{
+		"no selector": {
+			apiBinding:         binding.Build(),
+			wantBoundResources: []string{"gadgets", "widgets"},
+			wantSchemaStates: map[string]apisv1alpha1.SchemaBindingState{
+				"gadgets": apisv1alpha1.SchemaBindingStateEstablished,
+				"widgets": apisv1alpha1.SchemaBindingStateEstablished,
+			},
+			wantCondition: conditions.TrueCondition(apisv1alpha1.BindingUpToDate),
+		},
+		"subset selected": {
+			apiBinding:         binding.Build(),
+			selector:           []apisv1alpha1.GroupResource{{Group: "kcp.io", Resource: "widgets"}},
+			wantBoundResources: []string{"widgets"},
+			wantSchemaStates: map[string]apisv1alpha1.SchemaBindingState{
+				"gadgets": apisv1alpha1.SchemaBindingStateSkipped,
+				"widgets": apisv1alpha1.SchemaBindingStateEstablished,
+			},
+			wantCondition: conditions.TrueCondition(apisv1alpha1.BindingUpToDate),
+		},
+		"bound resource deselected": {
+			apiBinding:         rebinding.Build(),
+			selector:           []apisv1alpha1.GroupResource{{Group: "kcp.io", Resource: "gadgets"}},
+			wantBoundResources: []string{"widgets", "gadgets"},
+			wantSchemaStates: map[string]apisv1alpha1.SchemaBindingState{
+				"gadgets": apisv1alpha1.SchemaBindingStateEstablished,
+				"widgets": apisv1alpha1.SchemaBindingStateEstablished,
+			},
+			wantCondition: conditions.TrueCondition(apisv1alpha1.BindingUpToDate),
+		},
+		"nonexistent resource selected": {
+			apiBinding: binding.Build(),
+			selector: []apisv1alpha1.GroupResource{
+				{Group: "kcp.io", Resource: "widgets"},
+				{Group: "kcp.io", Resource: "sprockets"},
+			},
+			wantCondition: &conditionsv1alpha1.Condition{
+				Type:     apisv1alpha1.BindingUpToDate,
+				Status:   corev1.ConditionFalse,
+				Severity: conditionsv1alpha1.ConditionSeverityError,
+				Reason:   apisv1alpha1.ResourceNotExportedReason,
+				Message:  "APIExport org:some-workspace|some-export does not export the selected resource(s): sprockets.kcp.io",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			apiBinding := tc.apiBinding
+			apiBinding.Spec.ResourceSelector = tc.selector
+
+			_, err := Reconcile(context.Background(), deps, apiBinding)
+			require.NoError(t, err)
+
+			var boundResources []string
+			for _, boundResource := range apiBinding.Status.BoundResources {
+				boundResources = append(boundResources, boundResource.Resource)
+			}
+			require.Equal(t, tc.wantBoundResources, boundResources)
+
+			var schemaStates map[string]apisv1alpha1.SchemaBindingState
+			for _, schemaBinding := range apiBinding.Status.SchemaBindings {
+				if schemaStates == nil {
+					schemaStates = map[string]apisv1alpha1.SchemaBindingState{}
+				}
+				schemaStates[schemaBinding.Resource] = schemaBinding.State
+			}
+			require.Equal(t, tc.wantSchemaStates, schemaStates)
+
+			requireConditionMatches(t, apiBinding, tc.wantCondition)
+		})
+	}
+}
+
+func TestReconcileRemovedSchema(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "org-some-workspace",
+			},
+			Name: "some-export",
+		},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.kcp.io"},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash1"},
+	}
+	widgets := apisv1alpha1.BoundAPIResource{
+		Group:    "kcp.io",
+		Resource: "widgets",
+		Schema: apisv1alpha1.BoundAPIResourceSchema{
+			Name:         "today.widgets.kcp.io",
+			UID:          "todaywidgetsuid",
+			IdentityHash: "hash1",
+		},
+		StorageVersions: []string{"v1"},
+	}
+	gadgets := apisv1alpha1.BoundAPIResource{
+		Group:    "kcp.io",
+		Resource: "gadgets",
+		Schema: apisv1alpha1.BoundAPIResourceSchema{
+			Name:         "today.gadgets.kcp.io",
+			UID:          "todaygadgetsuid",
+			IdentityHash: "hash1",
+		},
+		StorageVersions: []string{"v1"},
+	}
+
+	tests := map[string]struct {
+		boundResources     []apisv1alpha1.BoundAPIResource
+		policy             RemovedSchemaPolicy
+		dryRun             bool
+		wantBoundResources []apisv1alpha1.BoundAPIResource
+		wantCondition      bool
+		wantEvent          bool
+	}{
+		"nothing removed": {
+			boundResources:     []apisv1alpha1.BoundAPIResource{widgets},
+			policy:             RemovedSchemaPolicyDelete,
+			wantBoundResources: []apisv1alpha1.BoundAPIResource{widgets},
+		},
+		"retained by default": {
+			boundResources:     []apisv1alpha1.BoundAPIResource{widgets, gadgets},
+			wantBoundResources: []apisv1alpha1.BoundAPIResource{widgets, gadgets},
+			wantCondition:      true,
+		},
+		"retained": {
+			boundResources:     []apisv1alpha1.BoundAPIResource{widgets, gadgets},
+			policy:             RemovedSchemaPolicyRetain,
+			wantBoundResources: []apisv1alpha1.BoundAPIResource{widgets, gadgets},
+			wantCondition:      true,
+		},
+		"deleted": {
+			boundResources:     []apisv1alpha1.BoundAPIResource{gadgets, widgets},
+			policy:             RemovedSchemaPolicyDelete,
+			wantBoundResources: []apisv1alpha1.BoundAPIResource{widgets},
+			wantEvent:          true,
+		},
+		"retained in dry run": {
+			boundResources:     []apisv1alpha1.BoundAPIResource{widgets, gadgets},
+			policy:             RemovedSchemaPolicyDelete,
+			dryRun:             true,
+			wantBoundResources: []apisv1alpha1.BoundAPIResource{widgets, gadgets},
+			wantCondition:      true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			recorder := events.NewFakeRecorder(10)
+			deps := newTestDependencies(export)
+			deps.GetCRD = func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+				return &apiextensionsv1.CustomResourceDefinition{
+					ObjectMeta: metav1.ObjectMeta{Name: name},
+					Status: apiextensionsv1.CustomResourceDefinitionStatus{
+						Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+							{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+						},
+						StoredVersions: []string{"v1"},
+					},
+				}, nil
+			}
+			deps.EventRecorder = recorder
+			deps.RemovedSchemaPolicy = tc.policy
+
+			builder := binding.DeepCopy().WithPhase(apisv1alpha1.APIBindingPhaseBound).WithBoundResources(tc.boundResources...)
+			if tc.dryRun {
+				builder = builder.WithAnnotation(apisv1alpha1.AnnotationDryRunKey, "true")
+			}
+			apiBinding := builder.Build()
+
+			_, err := Reconcile(context.Background(), deps, apiBinding)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantBoundResources, apiBinding.Status.BoundResources)
+
+			if tc.wantCondition {
+				requireConditionMatches(t, apiBinding, &conditionsv1alpha1.Condition{
+					Type:     apisv1alpha1.BoundResourcesExported,
+					Status:   corev1.ConditionFalse,
+					Reason:   apisv1alpha1.SchemaRemovedReason,
+					Severity: conditionsv1alpha1.ConditionSeverityWarning,
+					Message:  "gadgets.kcp.io",
+				})
+			} else {
+				require.False(t, conditions.Has(apiBinding, apisv1alpha1.BoundResourcesExported), "unexpected BoundResourcesExported condition")
+			}
+
+			var gotEvents []string
+			for len(recorder.Events) > 0 {
+				gotEvents = append(gotEvents, <-recorder.Events)
+			}
+			if tc.wantEvent {
+				require.Len(t, gotEvents, 1)
+				require.Contains(t, gotEvents[0], BoundResourcesRemovedEventReason)
+			} else {
+				require.Empty(t, gotEvents)
+			}
+		})
+	}
+}
+
+func TestReconcileCreatesCRDsInGroupResourceOrder(t *testing.T) {
+	newSchema := func(name, group, resource string) *apisv1alpha1.APIResourceSchema {
+		s := todayWidgetsAPIResourceSchema.DeepCopy()
+		s.Name = name
+		s.UID = types.UID(name)
+		s.Spec.Group = group
+		s.Spec.Names.Plural = resource
+		return s
+	}
+	schemas := map[string]*apisv1alpha1.APIResourceSchema{
+		"today.widgets.b.io":   newSchema("today.widgets.b.io", "b.io", "widgets"),
+		"today.gadgets.b.io":   newSchema("today.gadgets.b.io", "b.io", "gadgets"),
+		"today.widgets.a.io":   newSchema("today.widgets.a.io", "a.io", "widgets"),
+		"today.sprockets.c.io": newSchema("today.sprockets.c.io", "c.io", "sprockets"),
+	}
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "org-some-workspace",
+			},
+			Name: "some-export",
+		},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.b.io", "today.sprockets.c.io", "today.widgets.a.io", "today.gadgets.b.io"},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash1"},
+	}
+
+	var created []string
+	deps := newTestDependencies(export)
+	deps.GetAPIResourceSchema = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+		return schemas[name], nil
+	}
+	deps.GetCRD = getCRDNotFound
+	deps.CreateCRD = func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+		created = append(created, crd.Spec.Names.Plural+"."+crd.Spec.Group)
+		return crd, nil
+	}
+
+	_, err := Reconcile(context.Background(), deps, binding.Build())
+	require.NoError(t, err)
+	require.Equal(t, []string{"widgets.a.io", "gadgets.b.io", "widgets.b.io", "sprockets.c.io"}, created)
+}
+
+func TestReconcileCreatesCRDsConcurrently(t *testing.T) {
+	const numSchemas = 50
+	const concurrency = 4
+
+	schemas := map[string]*apisv1alpha1.APIResourceSchema{}
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "org-some-workspace",
+			},
+			Name: "some-export",
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash1"},
+	}
+	for i := 0; i < numSchemas; i++ {
+		s := todayWidgetsAPIResourceSchema.DeepCopy()
+		s.Name = fmt.Sprintf("today.widgets%02d.kcp.io", i)
+		s.UID = types.UID(s.Name)
+		s.Spec.Group = fmt.Sprintf("group%d.kcp.io", i%10)
+		s.Spec.Names.Plural = fmt.Sprintf("widgets%02d", i)
+		schemas[s.Name] = s
+		export.Spec.LatestResourceSchemas = append(export.Spec.LatestResourceSchemas, s.Name)
+	}
+
+	tests := map[string]struct {
+		createCRDErrors map[string]error
+		wantErrs        int
+		wantReason      string
+		wantInvalid     []string
+	}{
+		"all created": {},
+		"some fail": {
+			createCRDErrors: map[string]error{
+				"widgets03.group3.kcp.io": errors.New("boom"),
+				"widgets17.group7.kcp.io": errors.New("bang"),
+				"widgets42.group2.kcp.io": apierrors.NewInvalid(apiextensionsv1.Kind("CustomResourceDefinition"), "widgets42", field.ErrorList{field.Forbidden(field.NewPath("foo"), "details")}),
+			},
+			wantErrs:   2,
+			wantReason: apisv1alpha1.InternalErrorReason,
+		},
+		"only invalid": {
+			createCRDErrors: map[string]error{
+				"widgets12.group2.kcp.io": apierrors.NewInvalid(apiextensionsv1.Kind("CustomResourceDefinition"), "widgets12", field.ErrorList{field.Forbidden(field.NewPath("foo"), "details")}),
+				"widgets42.group2.kcp.io": apierrors.NewInvalid(apiextensionsv1.Kind("CustomResourceDefinition"), "widgets42", field.ErrorList{field.Forbidden(field.NewPath("foo"), "details")}),
+			},
+			wantReason:  apisv1alpha1.APIResourceSchemaInvalidReason,
+			wantInvalid: []string{"today.widgets12.kcp.io", "today.widgets42.kcp.io"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var lock sync.Mutex
+			var inFlight, maxInFlight int
+			created := sets.NewString()
+			createdByGroup := map[string][]string{}
+
+			deps := newTestDependencies(export)
+			deps.GetAPIResourceSchema = func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+				return schemas[name], nil
+			}
+			deps.GetCRD = getCRDNotFound
+			deps.CreateCRD = func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+				lock.Lock()
+				inFlight++
+				if inFlight > maxInFlight {
+					maxInFlight = inFlight
+				}
+				lock.Unlock()
+
+				time.Sleep(5 * time.Millisecond)
+
+				lock.Lock()
+				defer lock.Unlock()
+				inFlight--
+				gr := crd.Spec.Names.Plural + "." + crd.Spec.Group
+				created.Insert(gr)
+				createdByGroup[crd.Spec.Group] = append(createdByGroup[crd.Spec.Group], crd.Spec.Names.Plural)
+				if err := tc.createCRDErrors[gr]; err != nil {
+					return nil, err
+				}
+				return crd, nil
+			}
+			deps.CRDCreationConcurrency = concurrency
+
+			apiBinding := binding.Build()
+			_, err := Reconcile(context.Background(), deps, apiBinding)
+
+			require.Equal(t, numSchemas, created.Len(), "every CRD is attempted, even after failures")
+			require.LessOrEqual(t, maxInFlight, concurrency)
+			require.Greater(t, maxInFlight, 1, "CRDs are expected to be created in parallel")
+			for group, resources := range createdByGroup {
+				require.True(t, sort.StringsAreSorted(resources), "CRDs of group %s are expected to be created in resource order, got %v", group, resources)
+			}
+
+			if tc.wantReason == "" {
+				require.NoError(t, err)
+				require.Len(t, apiBinding.Status.SchemaBindings, numSchemas)
+				requireConditionMatches(t, apiBinding, conditions.FalseCondition(apisv1alpha1.InitialBindingCompleted, apisv1alpha1.WaitingForEstablishedReason, "", ""))
+				return
+			}
+
+			if tc.wantErrs == 0 {
+				require.NoError(t, err)
+			} else {
+				var agg utilerrors.Aggregate
+				require.ErrorAs(t, err, &agg)
+				require.Len(t, utilerrors.Flatten(agg).Errors(), tc.wantErrs)
+			}
+			require.Empty(t, apiBinding.Status.SchemaBindings)
+			requireConditionMatches(t, apiBinding, conditions.FalseCondition(apisv1alpha1.BindingUpToDate, tc.wantReason, "", ""))
+			for _, schemaName := range tc.wantInvalid {
+				require.Contains(t, conditions.GetMessage(apiBinding, apisv1alpha1.BindingUpToDate), schemaName, "every invalid schema is expected to be reported")
+			}
+		})
+	}
+}
+
+func TestReconcileAdoptsExistingCRD(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "org-some-workspace",
+			},
+			Name: "some-export",
+		},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.kcp.io"},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash1"},
+	}
+
+	tests := map[string]struct {
+		schemaCluster string
+		schemaName    string
+		wantUpdate    bool
+		wantErr       string
+	}{
+		"left over from the same schema": {
+			schemaCluster: "org-some-workspace",
+			schemaName:    "today.widgets.kcp.io",
+			wantUpdate:    true,
+		},
+		"owned by another schema": {
+			schemaCluster: "org-other-workspace",
+			schemaName:    "today.widgets.kcp.io",
+			wantErr:       "already exists for APIResourceSchema org-other-workspace|today.widgets.kcp.io",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			existing := &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: string(todayWidgetsAPIResourceSchema.UID),
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey:            SystemBoundCRDsClusterName.String(),
+						apisv1alpha1.AnnotationSchemaClusterKey: tc.schemaCluster,
+						apisv1alpha1.AnnotationSchemaNameKey:    tc.schemaName,
+					},
+				},
+			}
+
+			var updated *apiextensionsv1.CustomResourceDefinition
+			deps := newTestDependencies(export)
+			deps.GetCRD = func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+				// the informer cache has not seen the CRD yet
+				return nil, apierrors.NewNotFound(schema.GroupResource{}, name)
+			}
+			deps.GetLiveCRD = func(ctx context.Context, clusterName logicalcluster.Path, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+				return existing, nil
+			}
+			deps.CreateCRD = func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+				return nil, apierrors.NewAlreadyExists(apiextensionsv1.Resource("customresourcedefinitions"), crd.Name)
+			}
+			deps.UpdateCRD = func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+				updated = crd
+				return crd, nil
+			}
+
+			apiBinding := binding.Build()
+			_, err := Reconcile(context.Background(), deps, apiBinding)
+
+			if tc.wantErr != "" {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tc.wantErr)
+				require.Nil(t, updated, "a CRD of another schema must not be updated")
+				requireConditionMatches(t, apiBinding, conditions.FalseCondition(apisv1alpha1.BindingUpToDate, apisv1alpha1.InternalErrorReason, "", ""))
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, updated)
+			require.Equal(t, existing.Spec, updated.Spec, "the spec of an adopted CRD must not be touched")
+			require.Contains(t, updated.Annotations, apisv1alpha1.AnnotationBoundCRDKey)
+			require.NotContains(t, updated.Annotations, apisv1alpha1.AnnotationBoundCRDCreatedAtKey)
+			require.Equal(t, "today.widgets.kcp.io", updated.Annotations[apisv1alpha1.AnnotationSchemaNameKey])
+			require.Len(t, apiBinding.Status.SchemaBindings, 1)
+			require.Equal(t, apisv1alpha1.SchemaBindingStatePending, apiBinding.Status.SchemaBindings[0].State)
+		})
+	}
+}
+
+func TestReconcileRetriesBoundCRDUpdateOnConflict(t *testing.T) {
+	export := &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				logicalcluster.AnnotationKey: "org-some-workspace",
+			},
+			Name: "some-export",
+		},
+		Spec: apisv1alpha1.APIExportSpec{
+			LatestResourceSchemas: []string{"today.widgets.kcp.io"},
+		},
+		Status: apisv1alpha1.APIExportStatus{IdentityHash: "hash1"},
+	}
+
+	newCRD := func(resourceVersion string, drifted bool) *apiextensionsv1.CustomResourceDefinition {
+		crd := &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            string(todayWidgetsAPIResourceSchema.UID),
+				ResourceVersion: resourceVersion,
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{{Name: "v1", Served: true, Storage: true}},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{
+				Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{
+					{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue},
+				},
+				StoredVersions: []string{"v1"},
+			},
+		}
+		if drifted {
+			crd.Spec.Versions[0].Subresources = &apiextensionsv1.CustomResourceSubresources{
+				Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+			}
+		}
+		return crd
+	}
+
+	tests := map[string]struct {
+		conflicts           int
+		latestDrifted       bool
+		wantUpdateRVs       []string
+		wantErr             bool
+		wantBoundCRDDrifted bool
+	}{
+		"no conflict": {
+			latestDrifted:       true,
+			wantUpdateRVs:       []string{"1"},
+			wantBoundCRDDrifted: true,
+		},
+		"conflict on first update": {
+			conflicts:           1,
+			latestDrifted:       true,
+			wantUpdateRVs:       []string{"1", "2"},
+			wantBoundCRDDrifted: true,
+		},
+		"conflict with an update repairing the drift": {
+			conflicts:           1,
+			wantUpdateRVs:       []string{"1"},
+			wantBoundCRDDrifted: true,
+		},
+		"conflicts exhaust the retries": {
+			conflicts:     10,
+			latestDrifted: true,
+			wantUpdateRVs: []string{"1", "2", "2", "2", "2"},
+			wantErr:       true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var updateRVs []string
+			deps := newTestDependencies(export)
+			deps.GetCRD = func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+				// the informer cache does not see the conflicting update
+				return newCRD("1", true), nil
+			}
+			deps.RepairDriftedSubresources = true
+			deps.GetLiveCRD = func(ctx context.Context, clusterName logicalcluster.Path, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+				require.Equal(t, SystemBoundCRDsClusterName.Path(), clusterName)
+				return newCRD("2", tc.latestDrifted), nil
+			}
+			deps.UpdateCRD = func(ctx context.Context, clusterName logicalcluster.Path, crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceDefinition, error) {
+				updateRVs = append(updateRVs, crd.ResourceVersion)
+				require.Nil(t, crd.Spec.Versions[0].Subresources.Status, "subresources must be repaired")
+				if len(updateRVs) <= tc.conflicts {
+					return nil, apierrors.NewConflict(apiextensionsv1.Resource("customresourcedefinitions"), crd.Name, errors.New("changed"))
+				}
+				return crd, nil
+			}
+
+			apiBinding := binding.Build()
+			_, err := Reconcile(context.Background(), deps, apiBinding)
+			if tc.wantErr {
+				require.Error(t, err)
+				require.True(t, apierrors.IsConflict(utilerrors.Flatten(err.(utilerrors.Aggregate)).Errors()[0]), "expected a conflict, got %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.wantUpdateRVs, updateRVs)
+
+			if tc.wantBoundCRDDrifted {
+				requireConditionMatches(t, apiBinding, conditions.FalseCondition(apisv1alpha1.BindingUpToDate, apisv1alpha1.BoundCRDDriftedReason, "", ""))
+			}
+		})
+	}
+}
+
+func TestPropagateMetadata(t *testing.T) {
+	tests := map[string]struct {
+		labels          map[string]string
+		annotations     map[string]string
+		prefixes        []string
+		wantLabels      map[string]string
+		wantAnnotations map[string]string
+	}{
+		"no prefixes": {
+			labels:      map[string]string{"example.com/cost-center": "42"},
+			annotations: map[string]string{"example.com/owner": "team-a"},
+		},
+		"copied and filtered": {
+			labels:          map[string]string{"example.com/cost-center": "42", "other.io/tier": "gold"},
+			annotations:     map[string]string{"example.com/owner": "team-a", "kubectl.kubernetes.io/last-applied-configuration": "{}"},
+			prefixes:        []string{"example.com/"},
+			wantLabels:      map[string]string{"example.com/cost-center": "42"},
+			wantAnnotations: map[string]string{"example.com/owner": "team-a"},
+		},
+		"internal annotations are not clobbered": {
+			annotations: map[string]string{
+				apisv1alpha1.AnnotationSchemaNameKey:    "something-else",
+				apisv1alpha1.AnnotationSchemaClusterKey: "somewhere-else",
+				"apis.kcp.io/extra":                     "value",
+			},
+			prefixes:        []string{"apis.kcp.io/"},
+			wantAnnotations: map[string]string{"apis.kcp.io/extra": "value"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			schema := todayWidgetsAPIResourceSchema.DeepCopy()
+			schema.Labels = tc.labels
+			for k, v := range tc.annotations {
+				schema.Annotations[k] = v
+			}
+
+			crd, err := generateCRD(schema, SystemBoundCRDsClusterName)
+			require.NoError(t, err)
+			generated := crd.DeepCopy()
+
+			propagateMetadata(schema, crd, tc.prefixes)
+
+			wantAnnotations := generated.Annotations
+			for k, v := range tc.wantAnnotations {
+				wantAnnotations[k] = v
+			}
+			require.Equal(t, tc.wantLabels, crd.Labels)
+			require.Equal(t, wantAnnotations, crd.Annotations)
+		})
+	}
+}
+
+func TestCRDFromAPIResourceSchema(t *testing.T) {
+	tests := map[string]struct {
+		schema  *apisv1alpha1.APIResourceSchema
+		want    *apiextensionsv1.CustomResourceDefinition
+		wantErr bool
+	}{
+		"full schema": {
+			schema: &apisv1alpha1.APIResourceSchema{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey: "my-cluster",
+					},
+					Name: "my-name",
+					UID:  types.UID("my-uuid"),
+				},
+				Spec: apisv1alpha1.APIResourceSchemaSpec{
+					Group: "my-group",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{
+						Plural:     "widgets",
+						Singular:   "widget",
+						ShortNames: []string{"w"},
+						Kind:       "Widget",
+						ListKind:   "WidgetList",
+						Categories: []string{"things"},
+					},
+					Scope: apiextensionsv1.NamespaceScoped,
+					Versions: []apisv1alpha1.APIResourceVersion{
+						{
+							Name:               "v1",
+							Served:             true,
+							Storage:            false,
+							Deprecated:         true,
+							DeprecationWarning: pointer.StringPtr("deprecated!"),
+							Schema: runtime.RawExtension{
+								Raw: []byte(`
+{
+	"description": "foo",
+	"type": "object"
+}
+								`),
+							},
+							Subresources: apiextensionsv1.CustomResourceSubresources{
+								Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+								Scale: &apiextensionsv1.CustomResourceSubresourceScale{
+									SpecReplicasPath:   ".spec.replicas",
+									StatusReplicasPath: ".status.replicas",
+									LabelSelectorPath:  pointer.StringPtr(".status.selector"),
+								},
+							},
+							AdditionalPrinterColumns: []apiextensionsv1.CustomResourceColumnDefinition{
+								{
+									Name:        "My Column",
+									Type:        "string",
+									Format:      "string",
+									Description: "This is my column",
+									Priority:    1,
+									JSONPath:    ".spec.myColumn",
+								},
+							},
+						},
+						{
+							Name:       "v2",
+							Served:     true,
+							Storage:    true,
+							Deprecated: false,
+							Schema: runtime.RawExtension{
+								Raw: []byte(`
+{
+	"description": "foo",
+	"type": "object"
+}
+								`),
+							},
+							Subresources: apiextensionsv1.CustomResourceSubresources{
+								Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+								Scale: &apiextensionsv1.CustomResourceSubresourceScale{
+									SpecReplicasPath:   ".spec.replicas",
+									StatusReplicasPath: ".status.replicas",
+									LabelSelectorPath:  pointer.StringPtr(".status.selector"),
+								},
+							},
+							AdditionalPrinterColumns: []apiextensionsv1.CustomResourceColumnDefinition{
+								{
+									Name:        "My Column",
+									Type:        "string",
+									Format:      "string",
+									Description: "This is my column",
+									Priority:    1,
+									JSONPath:    ".spec.myColumn",
+								},
+							},
+						},
+					},
+				},
+			},
+			want: &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-uuid",
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey:            SystemBoundCRDsClusterName.String(),
+						apisv1alpha1.AnnotationBoundCRDKey:      "",
+						apisv1alpha1.AnnotationSchemaClusterKey: "my-cluster",
+						apisv1alpha1.AnnotationSchemaNameKey:    "my-name",
+					},
+				},
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Group: "my-group",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{
 						Plural:     "widgets",
 						Singular:   "widget",
 						ShortNames: []string{"w"},
@@ -804,12 +2269,223 @@ func TestCRDFromAPIResourceSchema(t *testing.T) {
 							},
 						},
 					},
-					Conversion:            nil,
+					Conversion: &apiextensionsv1.CustomResourceConversion{
+						Strategy: apiextensionsv1.NoneConverter,
+					},
 					PreserveUnknownFields: false,
 				},
 			},
 			wantErr: false,
 		},
+		"subresources per version": {
+			schema: &apisv1alpha1.APIResourceSchema{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey: "my-cluster",
+					},
+					Name: "my-name",
+					UID:  types.UID("my-uuid"),
+				},
+				Spec: apisv1alpha1.APIResourceSchemaSpec{
+					Group: "my-group",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{
+						Plural:   "widgets",
+						Singular: "widget",
+						Kind:     "Widget",
+						ListKind: "WidgetList",
+					},
+					Scope: apiextensionsv1.NamespaceScoped,
+					Versions: []apisv1alpha1.APIResourceVersion{
+						{
+							Name:    "v1",
+							Served:  true,
+							Storage: false,
+							Schema: runtime.RawExtension{
+								Raw: []byte(`{"type":"object"}`),
+							},
+							Subresources: apiextensionsv1.CustomResourceSubresources{
+								Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+								Scale: &apiextensionsv1.CustomResourceSubresourceScale{
+									SpecReplicasPath:   ".spec.replicas",
+									StatusReplicasPath: ".status.replicas",
+									LabelSelectorPath:  pointer.StringPtr(".status.selector"),
+								},
+							},
+						},
+						{
+							Name:    "v2",
+							Served:  true,
+							Storage: true,
+							Schema: runtime.RawExtension{
+								Raw: []byte(`{"type":"object"}`),
+							},
+							Subresources: apiextensionsv1.CustomResourceSubresources{
+								Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+							},
+						},
+					},
+				},
+			},
+			want: &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-uuid",
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey:            SystemBoundCRDsClusterName.String(),
+						apisv1alpha1.AnnotationBoundCRDKey:      "",
+						apisv1alpha1.AnnotationSchemaClusterKey: "my-cluster",
+						apisv1alpha1.AnnotationSchemaNameKey:    "my-name",
+					},
+				},
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Group: "my-group",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{
+						Plural:   "widgets",
+						Singular: "widget",
+						Kind:     "Widget",
+						ListKind: "WidgetList",
+					},
+					Scope: apiextensionsv1.NamespaceScoped,
+					Conversion: &apiextensionsv1.CustomResourceConversion{
+						Strategy: apiextensionsv1.NoneConverter,
+					},
+					Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+						{
+							Name:    "v1",
+							Served:  true,
+							Storage: false,
+							Schema: &apiextensionsv1.CustomResourceValidation{
+								OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+									Type: "object",
+								},
+							},
+							Subresources: &apiextensionsv1.CustomResourceSubresources{
+								Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+								Scale: &apiextensionsv1.CustomResourceSubresourceScale{
+									SpecReplicasPath:   ".spec.replicas",
+									StatusReplicasPath: ".status.replicas",
+									LabelSelectorPath:  pointer.StringPtr(".status.selector"),
+								},
+							},
+						},
+						{
+							Name:    "v2",
+							Served:  true,
+							Storage: true,
+							Schema: &apiextensionsv1.CustomResourceValidation{
+								OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+									Type: "object",
+								},
+							},
+							Subresources: &apiextensionsv1.CustomResourceSubresources{
+								Status: &apiextensionsv1.CustomResourceSubresourceStatus{},
+							},
+						},
+					},
+				},
+			},
+		},
+		"structural schema extensions are preserved": {
+			schema: &apisv1alpha1.APIResourceSchema{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey: "my-cluster",
+					},
+					Name: "my-name",
+					UID:  types.UID("my-uuid"),
+				},
+				Spec: apisv1alpha1.APIResourceSchemaSpec{
+					Group: "my-group",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{
+						Plural:   "widgets",
+						Singular: "widget",
+						Kind:     "Widget",
+						ListKind: "WidgetList",
+					},
+					Scope: apiextensionsv1.NamespaceScoped,
+					Versions: []apisv1alpha1.APIResourceVersion{
+						{
+							Name:    "v1",
+							Served:  true,
+							Storage: true,
+							Schema: runtime.RawExtension{
+								Raw: []byte(`
+{
+	"type": "object",
+	"properties": {
+		"spec": {
+			"type": "object",
+			"x-kubernetes-preserve-unknown-fields": true,
+			"properties": {
+				"replicas": {"type": "integer", "default": 1},
+				"port": {"x-kubernetes-int-or-string": true},
+				"template": {"type": "object", "x-kubernetes-embedded-resource": true, "x-kubernetes-preserve-unknown-fields": true},
+				"hosts": {"type": "array", "items": {"type": "string"}, "x-kubernetes-list-type": "set"},
+				"owner": {"type": "string", "nullable": true}
+			}
+		}
+	}
+}
+								`),
+							},
+						},
+					},
+				},
+			},
+			want: &apiextensionsv1.CustomResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "my-uuid",
+					Annotations: map[string]string{
+						logicalcluster.AnnotationKey:            SystemBoundCRDsClusterName.String(),
+						apisv1alpha1.AnnotationBoundCRDKey:      "",
+						apisv1alpha1.AnnotationSchemaClusterKey: "my-cluster",
+						apisv1alpha1.AnnotationSchemaNameKey:    "my-name",
+					},
+				},
+				Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+					Group: "my-group",
+					Names: apiextensionsv1.CustomResourceDefinitionNames{
+						Plural:   "widgets",
+						Singular: "widget",
+						Kind:     "Widget",
+						ListKind: "WidgetList",
+					},
+					Scope: apiextensionsv1.NamespaceScoped,
+					Conversion: &apiextensionsv1.CustomResourceConversion{
+						Strategy: apiextensionsv1.NoneConverter,
+					},
+					Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+						{
+							Name:    "v1",
+							Served:  true,
+							Storage: true,
+							Schema: &apiextensionsv1.CustomResourceValidation{
+								OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+									Type: "object",
+									Properties: map[string]apiextensionsv1.JSONSchemaProps{
+										"spec": {
+											Type:                   "object",
+											XPreserveUnknownFields: pointer.Bool(true),
+											Properties: map[string]apiextensionsv1.JSONSchemaProps{
+												"replicas": {Type: "integer", Default: &apiextensionsv1.JSON{Raw: []byte("1")}},
+												"port":     {XIntOrString: true},
+												"template": {Type: "object", XEmbeddedResource: true, XPreserveUnknownFields: pointer.Bool(true)},
+												"hosts": {
+													Type:      "array",
+													Items:     &apiextensionsv1.JSONSchemaPropsOrArray{Schema: &apiextensionsv1.JSONSchemaProps{Type: "string"}},
+													XListType: pointer.String("set"),
+												},
+												"owner": {Type: "string", Nullable: true},
+											},
+										},
+									},
+								},
+							},
+							Subresources: &apiextensionsv1.CustomResourceSubresources{},
+						},
+					},
+				},
+			},
+		},
 		"error when schema is invalid": {
 			schema: &apisv1alpha1.APIResourceSchema{
 				Spec: apisv1alpha1.APIResourceSchemaSpec{
@@ -827,7 +2503,7 @@ func TestCRDFromAPIResourceSchema(t *testing.T) {
 	}
 	for testName, tc := range tests {
 		t.Run(testName, func(t *testing.T) {
-			got, err := generateCRD(tc.schema)
+			got, err := generateCRD(tc.schema, SystemBoundCRDsClusterName)
 
 			if tc.wantErr != (err != nil) {
 				t.Fatalf("wantErr: %v, got %v", tc.wantErr, err)
@@ -870,11 +2546,24 @@ func (b *bindingBuilder) WithClusterName(clusterName logicalcluster.Name) *bindi
 	return b
 }
 
+func (b *bindingBuilder) WithAnnotation(key, value string) *bindingBuilder {
+	if b.Annotations == nil {
+		b.Annotations = make(map[string]string)
+	}
+	b.Annotations[key] = value
+	return b
+}
+
 func (b *bindingBuilder) WithName(name string) *bindingBuilder {
 	b.Name = name
 	return b
 }
 
+func (b *bindingBuilder) WithCreationTimestamp(t time.Time) *bindingBuilder {
+	b.CreationTimestamp = metav1.NewTime(t)
+	return b
+}
+
 func (b *bindingBuilder) WithoutWorkspaceReference() *bindingBuilder {
 	b.Spec.Reference.Export = nil
 	return b
@@ -916,6 +2605,11 @@ func (b *boundAPIResourceBuilder) WithSchema(name, uid string) *boundAPIResource
 	return b
 }
 
+func (b *boundAPIResourceBuilder) WithIdentityHash(identityHash string) *boundAPIResourceBuilder {
+	b.Schema.IdentityHash = identityHash
+	return b
+}
+
 func (b *boundAPIResourceBuilder) WithStorageVersions(v ...string) *boundAPIResourceBuilder {
 	b.StorageVersions = v
 	return b
@@ -26,6 +26,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
 )
 
 // byUID implements sort.Interface based on the UID field of CustomResourceDefinition.
@@ -42,8 +43,15 @@ type conflictChecker struct {
 	getCRD               func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error)
 	listCRDs             func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error)
 
+	// boundCRDsClusterName is the logical cluster bound CRDs live in.
+	boundCRDsClusterName logicalcluster.Name
+
 	boundCRDs    []*apiextensionsv1.CustomResourceDefinition
 	crdToBinding map[string]*apisv1alpha1.APIBinding
+
+	// pendingGroupResources maps the group resources that older APIBindings are actively binding,
+	// but have not bound yet, to those APIBindings.
+	pendingGroupResources map[apisv1alpha1.GroupResource]*apisv1alpha1.APIBinding
 }
 
 func (ncc *conflictChecker) getBoundCRDs(apiBindingToExclude *apisv1alpha1.APIBinding) error {
@@ -55,6 +63,7 @@ func (ncc *conflictChecker) getBoundCRDs(apiBindingToExclude *apisv1alpha1.APIBi
 	}
 
 	ncc.crdToBinding = make(map[string]*apisv1alpha1.APIBinding)
+	ncc.pendingGroupResources = make(map[apisv1alpha1.GroupResource]*apisv1alpha1.APIBinding)
 
 	for _, apiBinding := range apiBindings {
 		if apiBinding.Name == apiBindingToExclude.Name {
@@ -79,6 +88,7 @@ func (ncc *conflictChecker) getBoundCRDs(apiBindingToExclude *apisv1alpha1.APIBi
 		for _, boundResource := range apiBinding.Status.BoundResources {
 			boundSchemaUIDs.Insert(boundResource.Schema.UID)
 		}
+		pending := bindingPrecedes(apiBinding, apiBindingToExclude) && isActivelyBinding(apiBinding, apiExport)
 
 		for _, schemaName := range apiExport.Spec.LatestResourceSchemas {
 			schema, err := ncc.getAPIResourceSchema(logicalcluster.From(apiExport), schemaName)
@@ -87,10 +97,17 @@ func (ncc *conflictChecker) getBoundCRDs(apiBindingToExclude *apisv1alpha1.APIBi
 			}
 
 			if !boundSchemaUIDs.Has(string(schema.UID)) {
+				// Older bindings win over the binding being checked, such that concurrently created
+				// bindings for the same group resource don't both create and bind a CRD. Resources the
+				// older binding does not select are never bound by it.
+				if pending && selectsSchema(apiBinding, schema) {
+					gr := apisv1alpha1.GroupResource{Group: schema.Spec.Group, Resource: schema.Spec.Names.Plural}
+					ncc.pendingGroupResources[gr] = apiBinding
+				}
 				continue
 			}
 
-			crd, err := ncc.getCRD(SystemBoundCRDsClusterName, string(schema.UID))
+			crd, err := ncc.getCRD(ncc.boundCRDsClusterName, string(schema.UID))
 			if err != nil {
 				return err
 			}
@@ -123,9 +140,63 @@ func (ncc *conflictChecker) checkForConflicts(schema *apisv1alpha1.APIResourceSc
 		}
 	}
 
+	if err := ncc.pendingConflict(schema, apiBinding); err != nil {
+		return err
+	}
+
 	return ncc.gvrConflict(schema, apiBinding)
 }
 
+// pendingConflict returns an error if an older APIBinding is about to bind the same group resource as the schema.
+// Group resources the APIBinding has bound already are kept.
+func (ncc *conflictChecker) pendingConflict(schema *apisv1alpha1.APIResourceSchema, apiBinding *apisv1alpha1.APIBinding) error {
+	for _, boundResource := range apiBinding.Status.BoundResources {
+		if boundResource.Group == schema.Spec.Group && boundResource.Resource == schema.Spec.Names.Plural {
+			return nil
+		}
+	}
+
+	gr := apisv1alpha1.GroupResource{Group: schema.Spec.Group, Resource: schema.Spec.Names.Plural}
+	if conflict, found := ncc.pendingGroupResources[gr]; found {
+		return fmt.Errorf("naming conflict with older APIBinding %q that is binding %q group and %q resource as well",
+			conflict.Name, gr.Group, gr.Resource)
+	}
+	return nil
+}
+
+// isActivelyBinding returns true if the APIBinding is in the Binding phase and nothing keeps it from binding
+// the APIExport. Bindings that are deleted, in dry-run mode, pinned to another generation of the APIExport,
+// or blocked by a failing condition might never bind, and must not hold back newer bindings.
+func isActivelyBinding(apiBinding *apisv1alpha1.APIBinding, apiExport *apisv1alpha1.APIExport) bool {
+	if apiBinding.DeletionTimestamp != nil || apiBinding.Status.Phase != apisv1alpha1.APIBindingPhaseBinding {
+		return false
+	}
+	if apiBinding.Annotations[apisv1alpha1.AnnotationDryRunKey] == "true" {
+		return false
+	}
+	if pin := apiBinding.Spec.ExportGeneration; pin != 0 && pin != apiExport.Generation {
+		return false
+	}
+	if conditions.IsFalse(apiBinding, apisv1alpha1.APIExportValid) || conditions.IsFalse(apiBinding, apisv1alpha1.APIExportPinned) {
+		return false
+	}
+	// Waiting for the bound CRDs to be established is the only reason an APIBinding that is making progress
+	// reports. Every other reason, e.g. a naming conflict or an invalid schema, blocks it.
+	if conditions.IsFalse(apiBinding, apisv1alpha1.InitialBindingCompleted) &&
+		conditions.GetReason(apiBinding, apisv1alpha1.InitialBindingCompleted) != apisv1alpha1.WaitingForEstablishedReason {
+		return false
+	}
+	return true
+}
+
+// bindingPrecedes returns true if a was created before b. Ties are broken by name.
+func bindingPrecedes(a, b *apisv1alpha1.APIBinding) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
+
 func (ncc *conflictChecker) gvrConflict(schema *apisv1alpha1.APIResourceSchema, apiBinding *apisv1alpha1.APIBinding) error {
 	bindingClusterName := logicalcluster.From(apiBinding)
 	bindingClusterCRDs, err := ncc.listCRDs(bindingClusterName)
@@ -18,6 +18,7 @@ package apibinding
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/kcp-dev/logicalcluster/v3"
@@ -25,9 +26,12 @@ import (
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	conditionsv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/apis/conditions/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/third_party/conditions/util/conditions"
 )
 
 func TestNameConflictCheckerGetBoundCRDs(t *testing.T) {
@@ -126,6 +130,167 @@ func TestNameConflictCheckerGetBoundCRDs(t *testing.T) {
 	require.Equal(t, expectedMapping, ncc.crdToBinding)
 }
 
+func TestConflictCheckerOlderBindingWins(t *testing.T) {
+	older := new(bindingBuilder).
+		WithClusterName("root:org:ws").
+		WithName("older").
+		WithExportReference(logicalcluster.NewPath("root:org:exportWS"), "export1").
+		WithCreationTimestamp(time.Unix(100, 0)).
+		WithPhase(apisv1alpha1.APIBindingPhaseBinding).
+		Build()
+
+	newer := new(bindingBuilder).
+		WithClusterName("root:org:ws").
+		WithName("newer").
+		WithExportReference(logicalcluster.NewPath("root:org:exportWS"), "export2").
+		WithCreationTimestamp(time.Unix(200, 0)).
+		Build()
+
+	widgets := func(name, uid string) *apisv1alpha1.APIResourceSchema {
+		return &apisv1alpha1.APIResourceSchema{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(uid)},
+			Spec: apisv1alpha1.APIResourceSchemaSpec{
+				Group: "example.io",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "widgets"},
+			},
+		}
+	}
+	apiExports := map[string]*apisv1alpha1.APIExport{
+		"export1": {
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec:       apisv1alpha1.APIExportSpec{LatestResourceSchemas: []string{"export1-widgets"}},
+		},
+		"export2": {Spec: apisv1alpha1.APIExportSpec{LatestResourceSchemas: []string{"export2-widgets"}}},
+	}
+	apiResourceSchemas := map[string]*apisv1alpha1.APIResourceSchema{
+		"export1-widgets": widgets("export1-widgets", "e1-widgets"),
+		"export2-widgets": widgets("export2-widgets", "e2-widgets"),
+	}
+
+	tests := map[string]struct {
+		apiBinding    *apisv1alpha1.APIBinding
+		olderSelector []apisv1alpha1.GroupResource
+		blockOlder    func(older *apisv1alpha1.APIBinding)
+		schema        string
+		wantErr       bool
+	}{
+		"older binding wins": {
+			apiBinding: older,
+			schema:     "export1-widgets",
+		},
+		"newer binding conflicts": {
+			apiBinding: newer,
+			schema:     "export2-widgets",
+			wantErr:    true,
+		},
+		"newer binding keeps already bound group resource": {
+			apiBinding: new(bindingBuilder).
+				WithClusterName("root:org:ws").
+				WithName("newer").
+				WithExportReference(logicalcluster.NewPath("root:org:exportWS"), "export2").
+				WithCreationTimestamp(time.Unix(200, 0)).
+				WithBoundResources(apisv1alpha1.BoundAPIResource{
+					Group:    "example.io",
+					Resource: "widgets",
+					Schema:   apisv1alpha1.BoundAPIResourceSchema{Name: "export2-widgets", UID: "e2-widgets"},
+				}).
+				Build(),
+			schema: "export2-widgets",
+		},
+		"newer binding binds group resource not selected by older binding": {
+			apiBinding:    newer,
+			olderSelector: []apisv1alpha1.GroupResource{{Group: "example.io", Resource: "gadgets"}},
+			schema:        "export2-widgets",
+		},
+		"newer binding conflicts with older binding waiting for established CRDs": {
+			apiBinding: newer,
+			blockOlder: func(older *apisv1alpha1.APIBinding) {
+				conditions.MarkFalse(older, apisv1alpha1.InitialBindingCompleted, apisv1alpha1.WaitingForEstablishedReason, conditionsv1alpha1.ConditionSeverityInfo, "")
+			},
+			schema:  "export2-widgets",
+			wantErr: true,
+		},
+		"newer binding binds group resource of deleting older binding": {
+			apiBinding: newer,
+			blockOlder: func(older *apisv1alpha1.APIBinding) {
+				older.DeletionTimestamp = &metav1.Time{Time: time.Unix(300, 0)}
+			},
+			schema: "export2-widgets",
+		},
+		"newer binding binds group resource of bound older binding": {
+			apiBinding: newer,
+			blockOlder: func(older *apisv1alpha1.APIBinding) {
+				older.Status.Phase = apisv1alpha1.APIBindingPhaseBound
+			},
+			schema: "export2-widgets",
+		},
+		"newer binding binds group resource of dry-run older binding": {
+			apiBinding: newer,
+			blockOlder: func(older *apisv1alpha1.APIBinding) {
+				older.Annotations[apisv1alpha1.AnnotationDryRunKey] = "true"
+			},
+			schema: "export2-widgets",
+		},
+		"newer binding binds group resource of older binding pinned to another generation": {
+			apiBinding: newer,
+			blockOlder: func(older *apisv1alpha1.APIBinding) {
+				older.Spec.ExportGeneration = 1
+			},
+			schema: "export2-widgets",
+		},
+		"newer binding binds group resource of older binding with its own conflict": {
+			apiBinding: newer,
+			blockOlder: func(older *apisv1alpha1.APIBinding) {
+				conditions.MarkFalse(older, apisv1alpha1.InitialBindingCompleted, apisv1alpha1.NamingConflictsReason, conditionsv1alpha1.ConditionSeverityError, "")
+			},
+			schema: "export2-widgets",
+		},
+		"newer binding binds group resource of older binding with invalid APIExport": {
+			apiBinding: newer,
+			blockOlder: func(older *apisv1alpha1.APIBinding) {
+				conditions.MarkFalse(older, apisv1alpha1.APIExportValid, apisv1alpha1.APIExportNotFoundReason, conditionsv1alpha1.ConditionSeverityError, "")
+			},
+			schema: "export2-widgets",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			older := older.DeepCopy()
+			older.Spec.ResourceSelector = tc.olderSelector
+			if tc.blockOlder != nil {
+				tc.blockOlder(older)
+			}
+
+			ncc := &conflictChecker{
+				listAPIBindings: func(clusterName logicalcluster.Name) ([]*apisv1alpha1.APIBinding, error) {
+					return []*apisv1alpha1.APIBinding{older, newer}, nil
+				},
+				getAPIExport: func(path logicalcluster.Path, name string) (*apisv1alpha1.APIExport, error) {
+					return apiExports[name], nil
+				},
+				getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+					return apiResourceSchemas[name], nil
+				},
+				getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+					return &apiextensionsv1.CustomResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: name}}, nil
+				},
+				listCRDs: func(clusterName logicalcluster.Name) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+					return nil, nil
+				},
+			}
+
+			err := ncc.checkForConflicts(apiResourceSchemas[tc.schema], tc.apiBinding)
+			if tc.wantErr {
+				require.Error(t, err)
+				require.Contains(t, err.Error(), `older APIBinding "older"`)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestNamesConflict(t *testing.T) {
 	existingCRDFn := func(group, plural, singular, shortName, kind, listKind string) *apiextensionsv1.CustomResourceDefinition {
 		return &apiextensionsv1.CustomResourceDefinition{
@@ -17,37 +17,97 @@ limitations under the License.
 package apibinding
 
 import (
+	"context"
 	"sync"
+	"time"
 
-	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/clock"
 )
 
-// lockedStringSet guards a sets.String with an RWMutex.
+// lockedStringSet is a set of strings guarded by an RWMutex. If ttl is non-zero, strings are
+// forgotten ttl after they were added.
 type lockedStringSet struct {
-	lock sync.RWMutex
-	s    sets.String
+	lock  sync.RWMutex
+	s     map[string]time.Time
+	ttl   time.Duration
+	clock clock.Clock
 }
 
-func newLockedStringSet(s ...string) *lockedStringSet {
-	return &lockedStringSet{
-		s: sets.NewString(s...),
+func newLockedStringSet(ttl time.Duration, s ...string) *lockedStringSet {
+	l := &lockedStringSet{
+		ttl:   ttl,
+		clock: clock.RealClock{},
 	}
+	for _, x := range s {
+		l.Add(x)
+	}
+	return l
 }
 
 func (l *lockedStringSet) Add(s string) {
 	l.lock.Lock()
 	defer l.lock.Unlock()
-	l.s.Insert(s)
+	if l.s == nil {
+		l.s = map[string]time.Time{}
+	}
+	l.s[s] = l.now()
 }
 
 func (l *lockedStringSet) Remove(s string) {
 	l.lock.Lock()
 	defer l.lock.Unlock()
-	l.s.Delete(s)
+	delete(l.s, s)
+}
+
+// Pop removes s and returns whether it was in the set and not expired.
+func (l *lockedStringSet) Pop(s string) bool {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	added, found := l.s[s]
+	delete(l.s, s)
+	return found && !l.expired(added)
 }
 
 func (l *lockedStringSet) Has(s string) bool {
 	l.lock.RLock()
 	defer l.lock.RUnlock()
-	return l.s.Has(s)
+	added, found := l.s[s]
+	return found && !l.expired(added)
+}
+
+// Len returns the number of strings in the set, including expired ones not swept yet.
+func (l *lockedStringSet) Len() int {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return len(l.s)
+}
+
+// StartSweeper removes expired strings every ttl until ctx is done.
+func (l *lockedStringSet) StartSweeper(ctx context.Context) {
+	if l.ttl == 0 {
+		return
+	}
+	go wait.UntilWithContext(ctx, func(context.Context) { l.sweep() }, l.ttl)
+}
+
+func (l *lockedStringSet) sweep() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	for s, added := range l.s {
+		if l.expired(added) {
+			delete(l.s, s)
+		}
+	}
+}
+
+func (l *lockedStringSet) expired(added time.Time) bool {
+	return l.ttl != 0 && l.now().Sub(added) >= l.ttl
+}
+
+func (l *lockedStringSet) now() time.Time {
+	if l.clock == nil {
+		return time.Now()
+	}
+	return l.clock.Now()
 }
@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apibinding
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestLockedStringSetTTL(t *testing.T) {
+	clock := clocktesting.NewFakeClock(time.Now())
+	s := newLockedStringSet(time.Minute)
+	s.clock = clock
+
+	s.Add("a")
+	clock.Step(30 * time.Second)
+	s.Add("b")
+	require.True(t, s.Has("a"), "a must be respected before the TTL")
+	require.True(t, s.Has("b"))
+
+	clock.Step(30 * time.Second)
+	require.False(t, s.Has("a"), "a must be expired after the TTL")
+	require.True(t, s.Has("b"))
+
+	s.sweep()
+	require.Equal(t, 1, s.Len(), "a must be evicted by the sweeper")
+
+	clock.Step(30 * time.Second)
+	s.sweep()
+	require.False(t, s.Has("b"))
+	require.Zero(t, s.Len())
+}
+
+func TestLockedStringSetWithoutTTL(t *testing.T) {
+	clock := clocktesting.NewFakeClock(time.Now())
+	s := newLockedStringSet(0, "a")
+	s.clock = clock
+
+	clock.Step(24 * time.Hour)
+	s.sweep()
+	require.True(t, s.Has("a"))
+
+	s.Remove("a")
+	require.False(t, s.Has("a"))
+}
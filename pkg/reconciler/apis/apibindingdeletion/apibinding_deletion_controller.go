@@ -88,7 +88,7 @@ func NewController(
 		getAPIBinding: func(cluster logicalcluster.Name, name string) (*apisv1alpha1.APIBinding, error) {
 			return apiBindingInformer.Lister().Cluster(cluster).Get(name)
 		},
-		commit: committer.NewCommitter[*APIBinding, Patcher, *APIBindingSpec, *APIBindingStatus](kcpClusterClient.ApisV1alpha1().APIBindings()),
+		commit: committer.NewCommitter[*APIBinding, Patcher, *APIBindingSpec, *APIBindingStatus](kcpClusterClient.ApisV1alpha1().APIBindings(), committer.WithFieldManager(ControllerName)),
 	}
 
 	apiBindingInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
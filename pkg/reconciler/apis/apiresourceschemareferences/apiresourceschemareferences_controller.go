@@ -0,0 +1,226 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresourceschemareferences
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/client"
+	kcpclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster"
+	apisv1alpha1client "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/typed/apis/v1alpha1"
+	apisv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/indexers"
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+)
+
+const (
+	ControllerName = "kcp-apiresourceschema-references"
+)
+
+// NewController returns a new controller that annotates APIResourceSchemas with the names of
+// the APIExports referencing them.
+func NewController(
+	kcpClusterClient kcpclientset.ClusterInterface,
+	apiResourceSchemaInformer apisv1alpha1informers.APIResourceSchemaClusterInformer,
+	apiExportInformer apisv1alpha1informers.APIExportClusterInformer,
+) (*controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+
+	c := &controller{
+		queue: queue,
+		getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+			return apiResourceSchemaInformer.Lister().Cluster(clusterName).Get(name)
+		},
+		listAPIExportsByAPIResourceSchema: func(schema *apisv1alpha1.APIResourceSchema) ([]*apisv1alpha1.APIExport, error) {
+			key := client.ToClusterAwareKey(logicalcluster.From(schema).Path(), schema.Name)
+			return indexers.ByIndex[*apisv1alpha1.APIExport](apiExportInformer.Informer().GetIndexer(), indexers.APIExportByAPIResourceSchema, key)
+		},
+		commit: committer.NewStatuslessCommitter[*apisv1alpha1.APIResourceSchema, apisv1alpha1client.APIResourceSchemaInterface](kcpClusterClient.ApisV1alpha1().APIResourceSchemas(), committer.ShallowCopy[apisv1alpha1.APIResourceSchema]),
+	}
+
+	// The index is shared with the APIBinding controller.
+	if err := indexers.RegisterOrVerify(apiExportInformer.Informer().GetIndexer(), indexers.APIExportByAPIResourceSchema, indexers.IndexAPIExportByAPIResourceSchema); err != nil {
+		return nil, err
+	}
+
+	apiResourceSchemaInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueAPIResourceSchema(obj)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueAPIResourceSchema(newObj)
+		},
+	})
+
+	apiExportInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueFromAPIExport(nil, obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.enqueueFromAPIExport(oldObj, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueFromAPIExport(nil, obj)
+		},
+	})
+
+	return c, nil
+}
+
+// controller maintains the apis.kcp.io/referencing-apiexports annotation on APIResourceSchemas.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	getAPIResourceSchema              func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
+	listAPIExportsByAPIResourceSchema func(schema *apisv1alpha1.APIResourceSchema) ([]*apisv1alpha1.APIExport, error)
+
+	// commit creates a patch and submits it, if needed.
+	commit func(ctx context.Context, old, new *apisv1alpha1.APIResourceSchema) error
+}
+
+// enqueueAPIResourceSchema enqueues an APIResourceSchema.
+func (c *controller) enqueueAPIResourceSchema(obj interface{}) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(4).Info("queueing APIResourceSchema")
+	c.queue.Add(key)
+}
+
+// enqueueFromAPIExport enqueues the APIResourceSchemas referenced by the old and the new version
+// of an APIExport. Looking at both catches schemas that were dropped from the APIExport.
+func (c *controller) enqueueFromAPIExport(oldObj, newObj interface{}) {
+	schemas := sets.NewString()
+	var clusterName logicalcluster.Name
+	for _, obj := range []interface{}{oldObj, newObj} {
+		if obj == nil {
+			continue
+		}
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		export, ok := obj.(*apisv1alpha1.APIExport)
+		if !ok {
+			runtime.HandleError(fmt.Errorf("unexpected type %T", obj))
+			return
+		}
+		clusterName = logicalcluster.From(export)
+		schemas.Insert(export.Spec.LatestResourceSchemas...)
+	}
+
+	logger := logging.WithLogicalCluster(logging.WithReconciler(klog.Background(), ControllerName), clusterName)
+	for _, name := range schemas.List() {
+		key := kcpcache.ToClusterAwareKey(clusterName.String(), "", name)
+		logging.WithQueueKey(logger, key).V(4).Info("queueing APIResourceSchema via APIExport")
+		c.queue.Add(key)
+	}
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(4).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	clusterName, _, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return nil
+	}
+
+	schema, err := c.getAPIResourceSchema(clusterName, name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil // object deleted before we handled it
+		}
+		return err
+	}
+
+	old := schema
+	schema = schema.DeepCopy()
+
+	logger := logging.WithObject(klog.FromContext(ctx), schema)
+	ctx = klog.NewContext(ctx, logger)
+
+	if err := c.reconcile(ctx, schema); err != nil {
+		return err
+	}
+
+	// If the object being reconciled changed as a result, update it.
+	return c.commit(ctx, old, schema)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresourceschemareferences
+
+import (
+	"context"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+// reconcile sets the referencing APIExports annotation of the schema to the sorted names of the
+// APIExports that reference it, and removes the annotation if there are none.
+func (c *controller) reconcile(ctx context.Context, schema *apisv1alpha1.APIResourceSchema) error {
+	logger := klog.FromContext(ctx)
+
+	exports, err := c.listAPIExportsByAPIResourceSchema(schema)
+	if err != nil {
+		return err
+	}
+
+	names := sets.NewString()
+	for _, export := range exports {
+		names.Insert(export.Name)
+	}
+
+	if names.Len() == 0 {
+		if _, found := schema.Annotations[apisv1alpha1.AnnotationReferencingAPIExportsKey]; found {
+			logger.V(2).Info("no APIExport references APIResourceSchema anymore")
+			delete(schema.Annotations, apisv1alpha1.AnnotationReferencingAPIExportsKey)
+		}
+		return nil
+	}
+
+	value := strings.Join(names.List(), ",")
+	if schema.Annotations[apisv1alpha1.AnnotationReferencingAPIExportsKey] != value {
+		logger.V(2).Info("updating referencing APIExports", "apiExports", value)
+		if schema.Annotations == nil {
+			schema.Annotations = map[string]string{}
+		}
+		schema.Annotations[apisv1alpha1.AnnotationReferencingAPIExportsKey] = value
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apiresourceschemareferences
+
+import (
+	"context"
+	"testing"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/client"
+	"github.com/kcp-dev/kcp/pkg/indexers"
+)
+
+func newExport(name string, schemas ...string) *apisv1alpha1.APIExport {
+	return &apisv1alpha1.APIExport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+		},
+		Spec: apisv1alpha1.APIExportSpec{LatestResourceSchemas: schemas},
+	}
+}
+
+func TestReconcileReferencingAPIExports(t *testing.T) {
+	schema := &apisv1alpha1.APIResourceSchema{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "today.widgets.kcp.io",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+		},
+	}
+
+	exportIndexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{
+		indexers.APIExportByAPIResourceSchema: indexers.IndexAPIExportByAPIResourceSchema,
+	})
+
+	var committed *apisv1alpha1.APIResourceSchema
+	c := &controller{
+		getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
+			return schema, nil
+		},
+		listAPIExportsByAPIResourceSchema: func(schema *apisv1alpha1.APIResourceSchema) ([]*apisv1alpha1.APIExport, error) {
+			key := client.ToClusterAwareKey(logicalcluster.From(schema).Path(), schema.Name)
+			return indexers.ByIndex[*apisv1alpha1.APIExport](exportIndexer, indexers.APIExportByAPIResourceSchema, key)
+		},
+		commit: func(ctx context.Context, old, new *apisv1alpha1.APIResourceSchema) error {
+			committed = new
+			return nil
+		},
+	}
+
+	key := kcpcache.ToClusterAwareKey("root:org", "", schema.Name)
+	steps := []struct {
+		name       string
+		mutate     func()
+		wantExists bool
+		wantValue  string
+	}{
+		{
+			name:   "not referenced",
+			mutate: func() {},
+		},
+		{
+			name: "referenced by one export",
+			mutate: func() {
+				require.NoError(t, exportIndexer.Add(newExport("b-export", schema.Name)))
+				require.NoError(t, exportIndexer.Add(newExport("unrelated", "today.gadgets.kcp.io")))
+			},
+			wantExists: true,
+			wantValue:  "b-export",
+		},
+		{
+			name: "referenced by two exports",
+			mutate: func() {
+				require.NoError(t, exportIndexer.Add(newExport("a-export", "today.gadgets.kcp.io", schema.Name)))
+			},
+			wantExists: true,
+			wantValue:  "a-export,b-export",
+		},
+		{
+			name: "dropped from one export",
+			mutate: func() {
+				require.NoError(t, exportIndexer.Update(newExport("b-export", "today.gadgets.kcp.io")))
+			},
+			wantExists: true,
+			wantValue:  "a-export",
+		},
+		{
+			name: "last export deleted",
+			mutate: func() {
+				require.NoError(t, exportIndexer.Delete(newExport("a-export")))
+			},
+		},
+	}
+
+	for _, step := range steps {
+		step.mutate()
+
+		committed = nil
+		require.NoError(t, c.process(context.Background(), key), step.name)
+		require.NotNil(t, committed, step.name)
+
+		value, found := committed.Annotations[apisv1alpha1.AnnotationReferencingAPIExportsKey]
+		require.Equal(t, step.wantExists, found, step.name)
+		require.Equal(t, step.wantValue, value, step.name)
+
+		schema = committed
+	}
+}
+
+func TestEnqueueFromAPIExport(t *testing.T) {
+	c := &controller{
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName),
+	}
+	defer c.queue.ShutDown()
+
+	c.enqueueFromAPIExport(newExport("export", "today.widgets.kcp.io", "today.gadgets.kcp.io"), newExport("export", "today.gadgets.kcp.io", "today.sprockets.kcp.io"))
+
+	var keys []string
+	for c.queue.Len() > 0 {
+		k, _ := c.queue.Get()
+		keys = append(keys, k.(string))
+		c.queue.Done(k)
+	}
+	require.ElementsMatch(t, []string{
+		"root:org|today.gadgets.kcp.io",
+		"root:org|today.sprockets.kcp.io",
+		"root:org|today.widgets.kcp.io",
+	}, keys)
+}
@@ -40,7 +40,6 @@ import (
 	apisv1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/apis/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/pkg/logging"
-	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
 )
 
 const (
@@ -55,11 +54,13 @@ func NewController(
 	crdInformer kcpapiextensionsv1informers.CustomResourceDefinitionClusterInformer,
 	crdClusterClient kcpapiextensionsclientset.ClusterInterface,
 	apiBindingInformer apisv1alpha1informers.APIBindingClusterInformer,
+	boundCRDsClusterName logicalcluster.Name,
 ) (*controller, error) {
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
 
 	c := &controller{
-		queue: queue,
+		queue:                queue,
+		boundCRDsClusterName: boundCRDsClusterName,
 		getCRD: func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error) {
 			return crdInformer.Lister().Cluster(clusterName).Get(name)
 		},
@@ -67,7 +68,7 @@ func NewController(
 			return indexers.ByIndex[*apisv1alpha1.APIBinding](apiBindingInformer.Informer().GetIndexer(), indexers.APIBindingByBoundResourceUID, name)
 		},
 		deleteCRD: func(ctx context.Context, name string) error {
-			return crdClusterClient.ApiextensionsV1().CustomResourceDefinitions().Cluster(apibinding.SystemBoundCRDsClusterName.Path()).Delete(ctx, name, metav1.DeleteOptions{})
+			return crdClusterClient.ApiextensionsV1().CustomResourceDefinitions().Cluster(boundCRDsClusterName.Path()).Delete(ctx, name, metav1.DeleteOptions{})
 		},
 	}
 
@@ -81,7 +82,7 @@ func NewController(
 	crdInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: func(obj interface{}) bool {
 			crd := obj.(*apiextensionsv1.CustomResourceDefinition)
-			return logicalcluster.From(crd) == apibinding.SystemBoundCRDsClusterName
+			return logicalcluster.From(crd) == boundCRDsClusterName
 		},
 		Handler: cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
@@ -114,6 +115,9 @@ func NewController(
 type controller struct {
 	queue workqueue.RateLimitingInterface
 
+	// boundCRDsClusterName is the logical cluster the bound CRDs live in.
+	boundCRDsClusterName logicalcluster.Name
+
 	getCRD                           func(clusterName logicalcluster.Name, name string) (*apiextensionsv1.CustomResourceDefinition, error)
 	getAPIBindingsByBoundResourceUID func(name string) ([]*apisv1alpha1.APIBinding, error)
 	deleteCRD                        func(ctx context.Context, name string) error
@@ -152,7 +156,7 @@ func (c *controller) enqueueFromAPIBinding(oldBinding, newBinding *apisv1alpha1.
 	}
 
 	for uid := range uidSet {
-		key := kcpcache.ToClusterAwareKey(apibinding.SystemBoundCRDsClusterName.String(), "", uid)
+		key := kcpcache.ToClusterAwareKey(c.boundCRDsClusterName.String(), "", uid)
 		logging.WithQueueKey(logger, key).V(2).Info("queueing CRD via APIBinding")
 		c.queue.Add(key)
 	}
@@ -23,7 +23,12 @@ import (
 	"github.com/kcp-dev/logicalcluster/v3"
 
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/kubernetes/pkg/apis/rbac"
+
+	"github.com/kcp-dev/kcp/pkg/indexers"
 )
 
 const ClusterRoleBindingByClusterRoleName = "indexClusterRoleBindingByClusterRole"
@@ -41,3 +46,60 @@ func IndexClusterRoleBindingByClusterRoleName(obj interface{}) ([]string, error)
 
 	return []string{}, nil
 }
+
+const ClusterRoleByGrantedResource = "indexClusterRoleByGrantedResource"
+
+// IndexClusterRoleByGrantedResource indexes a ClusterRole by the group resources its rules grant
+// access to, as <cluster>|<resource>.<group>. Wildcards are kept as is, i.e. a rule for all
+// resources of all groups is indexed under <cluster>|*.*. Subresources stay part of the resource.
+func IndexClusterRoleByGrantedResource(obj interface{}) ([]string, error) {
+	cr, ok := obj.(*rbacv1.ClusterRole)
+	if !ok {
+		return []string{}, fmt.Errorf("obj %T is not a ClusterRole", obj)
+	}
+
+	clusterName := logicalcluster.From(cr)
+	keys := sets.NewString()
+	for _, rule := range cr.Rules {
+		for _, group := range rule.APIGroups {
+			for _, resource := range rule.Resources {
+				keys.Insert(grantedResourceKey(clusterName, schema.GroupResource{Group: group, Resource: resource}))
+			}
+		}
+	}
+
+	return keys.List(), nil
+}
+
+// ClusterRolesByGrantedResource returns the ClusterRoles in the given cluster that grant access to
+// the given group resource, including through wildcard groups or resources.
+func ClusterRolesByGrantedResource(indexer cache.Indexer, clusterName logicalcluster.Name, gr schema.GroupResource) ([]*rbacv1.ClusterRole, error) {
+	candidates := []schema.GroupResource{
+		gr,
+		{Group: gr.Group, Resource: rbacv1.ResourceAll},
+		{Group: rbacv1.APIGroupAll, Resource: gr.Resource},
+		{Group: rbacv1.APIGroupAll, Resource: rbacv1.ResourceAll},
+	}
+
+	seen := sets.NewString()
+	var ret []*rbacv1.ClusterRole
+	for _, candidate := range candidates {
+		crs, err := indexers.ByIndex[*rbacv1.ClusterRole](indexer, ClusterRoleByGrantedResource, grantedResourceKey(clusterName, candidate))
+		if err != nil {
+			return nil, err
+		}
+		for _, cr := range crs {
+			if seen.Has(cr.Name) {
+				continue
+			}
+			seen.Insert(cr.Name)
+			ret = append(ret, cr)
+		}
+	}
+
+	return ret, nil
+}
+
+func grantedResourceKey(clusterName logicalcluster.Name, gr schema.GroupResource) string {
+	return kcpcache.ToClusterAwareKey(clusterName.String(), "", gr.String())
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicationclusterrole
+
+import (
+	"testing"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newClusterRole(name string, rules ...rbacv1.PolicyRule) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+		},
+		Rules: rules,
+	}
+}
+
+func TestIndexClusterRoleByGrantedResource(t *testing.T) {
+	tests := map[string]struct {
+		obj     interface{}
+		want    []string
+		wantErr bool
+	}{
+		"not a ClusterRole": {
+			obj:     "not a ClusterRole",
+			want:    []string{},
+			wantErr: true,
+		},
+		"specific resources": {
+			obj: newClusterRole("specific",
+				rbacv1.PolicyRule{APIGroups: []string{"apis.kcp.io"}, Resources: []string{"apiexports", "apiexports/content"}, Verbs: []string{"get"}},
+				rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get"}},
+			),
+			want: []string{"root:org|apiexports.apis.kcp.io", "root:org|apiexports/content.apis.kcp.io", "root:org|configmaps"},
+		},
+		"wildcards": {
+			obj: newClusterRole("wildcards",
+				rbacv1.PolicyRule{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+				rbacv1.PolicyRule{APIGroups: []string{"apis.kcp.io"}, Resources: []string{"*"}, Verbs: []string{"get"}},
+			),
+			want: []string{"root:org|*.*", "root:org|*.apis.kcp.io"},
+		},
+		"non-resource URLs only": {
+			obj: newClusterRole("urls",
+				rbacv1.PolicyRule{NonResourceURLs: []string{"/healthz"}, Verbs: []string{"get"}},
+			),
+			want: []string{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := IndexClusterRoleByGrantedResource(tt.obj)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestClusterRolesByGrantedResource(t *testing.T) {
+	indexer := cache.NewIndexer(kcpcache.MetaClusterNamespaceKeyFunc, cache.Indexers{
+		ClusterRoleByGrantedResource: IndexClusterRoleByGrantedResource,
+	})
+	for _, cr := range []*rbacv1.ClusterRole{
+		newClusterRole("exports", rbacv1.PolicyRule{APIGroups: []string{"apis.kcp.io"}, Resources: []string{"apiexports"}, Verbs: []string{"bind"}}),
+		newClusterRole("all-apis", rbacv1.PolicyRule{APIGroups: []string{"apis.kcp.io"}, Resources: []string{"*"}, Verbs: []string{"get"}}),
+		newClusterRole("any-exports", rbacv1.PolicyRule{APIGroups: []string{"*"}, Resources: []string{"apiexports"}, Verbs: []string{"get"}}),
+		newClusterRole("admin", rbacv1.PolicyRule{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}}, rbacv1.PolicyRule{APIGroups: []string{"apis.kcp.io"}, Resources: []string{"apiexports"}, Verbs: []string{"*"}}),
+		newClusterRole("configmaps", rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get"}}),
+	} {
+		require.NoError(t, indexer.Add(cr))
+	}
+
+	tests := map[string]struct {
+		gr   schema.GroupResource
+		want []string
+	}{
+		"specific and wildcard matches": {
+			gr:   schema.GroupResource{Group: "apis.kcp.io", Resource: "apiexports"},
+			want: []string{"admin", "all-apis", "any-exports", "exports"},
+		},
+		"group wildcard only": {
+			gr:   schema.GroupResource{Group: "apis.kcp.io", Resource: "apibindings"},
+			want: []string{"admin", "all-apis"},
+		},
+		"core group": {
+			gr:   schema.GroupResource{Resource: "configmaps"},
+			want: []string{"admin", "configmaps"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			crs, err := ClusterRolesByGrantedResource(indexer, "root:org", tt.gr)
+			require.NoError(t, err)
+
+			var got []string
+			for _, cr := range crs {
+				got = append(got, cr.Name)
+			}
+			require.ElementsMatch(t, tt.want, got)
+		})
+	}
+
+	crs, err := ClusterRolesByGrantedResource(indexer, "root:other", schema.GroupResource{Group: "apis.kcp.io", Resource: "apiexports"})
+	require.NoError(t, err)
+	require.Empty(t, crs, "ClusterRoles of other clusters must not match")
+}
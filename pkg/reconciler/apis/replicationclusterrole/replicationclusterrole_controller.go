@@ -19,6 +19,7 @@ package replicationclusterrole
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
@@ -29,33 +30,78 @@ import (
 
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/wait"
 	rbacclientv1 "k8s.io/client-go/kubernetes/typed/rbac/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	"github.com/kcp-dev/kcp/pkg/apis/core"
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	corev1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/core/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/clusterdeletion"
 	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
 )
 
 const (
 	ControllerName = "kcp-apiexport-replication-clusterrole"
+
+	// DefaultReplicateFor is the value ClusterRoles are marked for replication with by default.
+	DefaultReplicateFor = "apis.kcp.io"
 )
 
+// ReplicateForOrDefault returns replicateFor, or DefaultReplicateFor if it is empty. It fails if
+// the value cannot be an entry of the replicate annotation. The replication controllers of the
+// other RBAC objects share it, such that all objects of a policy are replicated for the same value.
+func ReplicateForOrDefault(replicateFor string) (string, error) {
+	if replicateFor == "" {
+		return DefaultReplicateFor, nil
+	}
+	if errs := validation.IsQualifiedName(replicateFor); len(errs) > 0 {
+		return "", fmt.Errorf("invalid replication value %q: %s", replicateFor, strings.Join(errs, ", "))
+	}
+	return replicateFor, nil
+}
+
 // NewController returns a new controller for labelling ClusterRole that should be replicated.
+// ClusterRoles are marked for replication with the replicateFor value in the replicate annotation,
+// which defaults to DefaultReplicateFor if empty. Only ClusterRoles in logical clusters whose
+// LogicalCluster matches workspaceSelector are considered; a nil selector selects all of them.
+// ClusterRoles of logical clusters that stop matching are no longer marked for replication.
 func NewController(
 	kubeClusterClient kcpkubernetesclientset.ClusterInterface,
 	clusterRoleInformer kcprbacinformers.ClusterRoleClusterInformer,
 	clusterRoleBindingInformer kcprbacinformers.ClusterRoleBindingClusterInformer,
+	logicalClusterInformer corev1alpha1informers.LogicalClusterClusterInformer,
+	replicateFor string,
+	workspaceSelector labels.Selector,
 ) (*controller, error) {
+	replicateFor, err := ReplicateForOrDefault(replicateFor)
+	if err != nil {
+		return nil, err
+	}
+	if workspaceSelector == nil {
+		workspaceSelector = labels.Everything()
+	}
+
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
 
 	c := &controller{
-		queue: queue,
+		queue:        queue,
+		replicateFor: replicateFor,
+
+		workspaceSelector: workspaceSelector,
+		getLogicalCluster: func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error) {
+			return logicalClusterInformer.Lister().Cluster(clusterName).Get(corev1alpha1.LogicalClusterName)
+		},
+		isLogicalClusterDeleting: clusterdeletion.NewInformerIsDeletingFunc(logicalClusterInformer),
 
 		kubeClusterClient: kubeClusterClient,
 
@@ -65,12 +111,15 @@ func NewController(
 		clusterRoleBindingLister:  clusterRoleBindingInformer.Lister(),
 		clusterRoleBindingIndexer: clusterRoleBindingInformer.Informer().GetIndexer(),
 
-		commit: committer.NewStatuslessCommitter[*rbacv1.ClusterRole, rbacclientv1.ClusterRoleInterface](kubeClusterClient.RbacV1().ClusterRoles(), committer.ShallowCopy[rbacv1.ClusterRole]),
+		commit: committer.NewStatuslessCommitter[*rbacv1.ClusterRole, rbacclientv1.ClusterRoleInterface](kubeClusterClient.RbacV1().ClusterRoles(), committer.ShallowCopy[rbacv1.ClusterRole], committer.WithDiffLogging(), committer.WithFieldManager(ControllerName)),
 	}
 
 	indexers.AddIfNotPresentOrDie(clusterRoleBindingInformer.Informer().GetIndexer(), cache.Indexers{
 		ClusterRoleBindingByClusterRoleName: IndexClusterRoleBindingByClusterRoleName,
 	})
+	indexers.AddIfNotPresentOrDie(clusterRoleInformer.Informer().GetIndexer(), cache.Indexers{
+		ClusterRoleByGrantedResource: IndexClusterRoleByGrantedResource,
+	})
 
 	clusterRoleInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
@@ -96,6 +145,17 @@ func NewController(
 		},
 	})
 
+	if !workspaceSelector.Empty() {
+		logicalClusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.enqueueLogicalCluster(nil, obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				c.enqueueLogicalCluster(oldObj, newObj)
+			},
+		})
+	}
+
 	return c, nil
 }
 
@@ -104,6 +164,17 @@ func NewController(
 type controller struct {
 	queue workqueue.RateLimitingInterface
 
+	// replicateFor is the value ClusterRoles are marked for replication with.
+	replicateFor string
+
+	// workspaceSelector selects the logical clusters whose ClusterRoles are reconciled.
+	workspaceSelector labels.Selector
+	getLogicalCluster func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error)
+
+	// isLogicalClusterDeleting returns whether a logical cluster is being deleted. Its ClusterRoles are not
+	// reconciled then. A nil func treats all logical clusters as not deleting.
+	isLogicalClusterDeleting func(clusterName logicalcluster.Name) bool
+
 	kubeClusterClient kcpkubernetesclientset.ClusterInterface
 
 	clusterRoleLister  kcprbaclisters.ClusterRoleClusterLister
@@ -124,11 +195,80 @@ func (c *controller) enqueueClusterRole(obj interface{}, values ...interface{})
 		return
 	}
 
-	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	clusterName, _, _, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger := logging.WithQueueKey(logging.WithLogicalCluster(logging.WithReconciler(klog.Background(), ControllerName), clusterName), key)
+	if !c.selectsCluster(clusterName) && !c.isMarkedForReplication(obj) {
+		logger.V(5).Info("skipping ClusterRole in unselected logical cluster")
+		return
+	}
 	logger.V(4).WithValues(values...).Info("queueing ClusterRole")
 	c.queue.Add(key)
 }
 
+// selectsCluster returns whether the LogicalCluster of the given logical cluster matches the
+// workspace selector. Logical clusters whose LogicalCluster is not known yet are not selected,
+// they are enqueued by enqueueLogicalCluster when it shows up.
+func (c *controller) selectsCluster(clusterName logicalcluster.Name) bool {
+	if c.workspaceSelector == nil || c.workspaceSelector.Empty() {
+		return true
+	}
+
+	logicalCluster, err := c.getLogicalCluster(clusterName)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			runtime.HandleError(err)
+		}
+		return false
+	}
+	return c.workspaceSelector.Matches(labels.Set(logicalCluster.Labels))
+}
+
+// isMarkedForReplication returns whether obj is a ClusterRole marked for replication with the
+// replicateFor value of the controller.
+func (c *controller) isMarkedForReplication(obj interface{}) bool {
+	cr, ok := obj.(*rbacv1.ClusterRole)
+	if !ok {
+		return false
+	}
+	value, found := cr.Annotations[core.ReplicateAnnotationKey]
+	return found && sets.NewString(strings.Split(value, ",")...).Has(c.replicateFor)
+}
+
+// enqueueLogicalCluster enqueues the ClusterRoles of a logical cluster when its LogicalCluster
+// starts or stops matching the workspace selector. When it stops matching, enqueueClusterRole
+// only lets ClusterRoles through that are still marked for replication.
+func (c *controller) enqueueLogicalCluster(oldObj, newObj interface{}) {
+	logicalCluster, ok := newObj.(*corev1alpha1.LogicalCluster)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("unexpected type %T", newObj))
+		return
+	}
+	selected := c.workspaceSelector.Matches(labels.Set(logicalCluster.Labels))
+	if old, ok := oldObj.(*corev1alpha1.LogicalCluster); ok && c.workspaceSelector.Matches(labels.Set(old.Labels)) == selected {
+		return
+	}
+
+	reason := "LogicalCluster selected"
+	if !selected {
+		reason = "LogicalCluster not selected"
+	}
+
+	clusterName := logicalcluster.From(logicalCluster)
+	crs, err := c.clusterRoleLister.Cluster(clusterName).List(labels.Everything())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, cr := range crs {
+		c.enqueueClusterRole(cr, "reason", reason)
+	}
+}
+
 func (c *controller) enqueueClusterRoleBinding(obj interface{}) {
 	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
 		obj = tombstone.Obj
@@ -210,6 +350,10 @@ func (c *controller) process(ctx context.Context, key string) (bool, error) {
 		runtime.HandleError(err)
 		return false, nil
 	}
+	if c.isLogicalClusterDeleting != nil && c.isLogicalClusterDeleting(parent) {
+		klog.FromContext(ctx).V(4).Info("skipping ClusterRole in deleting logical cluster")
+		return false, nil
+	}
 	cr, err := c.clusterRoleLister.Cluster(parent).Get(name)
 	if err != nil {
 		if errors.IsNotFound(err) {
@@ -24,6 +24,8 @@ import (
 	"github.com/kcp-dev/logicalcluster/v3"
 
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/sets"
 
@@ -34,7 +36,22 @@ import (
 )
 
 func (c *controller) reconcile(ctx context.Context, rb *rbacv1.ClusterRole) (bool, error) {
+	if c.workspaceSelector != nil && !c.workspaceSelector.Empty() {
+		logicalCluster, err := c.getLogicalCluster(logicalcluster.From(rb))
+		if errors.IsNotFound(err) {
+			return false, nil // enqueued again when the LogicalCluster shows up
+		} else if err != nil {
+			return false, err
+		}
+		if !c.workspaceSelector.Matches(labels.Set(logicalCluster.Labels)) {
+			// the logical cluster is not selected (anymore), stop replicating its ClusterRoles.
+			rb.Annotations, _ = kcpcorehelper.DontReplicateFor(rb.Annotations, c.replicateFor)
+			return false, nil
+		}
+	}
+
 	r := &reconciler{
+		replicateFor: c.replicateFor,
 		getReferencingClusterRoleBindings: func(cluster logicalcluster.Name, name string) ([]*rbacv1.ClusterRoleBinding, error) {
 			key := kcpcache.ToClusterAwareKey(cluster.String(), "", name)
 			return indexers.ByIndex[*rbacv1.ClusterRoleBinding](c.clusterRoleBindingIndexer, ClusterRoleBindingByClusterRoleName, key)
@@ -44,6 +61,7 @@ func (c *controller) reconcile(ctx context.Context, rb *rbacv1.ClusterRole) (boo
 }
 
 type reconciler struct {
+	replicateFor                      string
 	getReferencingClusterRoleBindings func(cluster logicalcluster.Name, name string) ([]*rbacv1.ClusterRoleBinding, error)
 }
 
@@ -64,9 +82,9 @@ func (r *reconciler) reconcile(ctx context.Context, cr *rbacv1.ClusterRole) (boo
 	}
 
 	if replicate {
-		cr.Annotations, _ = kcpcorehelper.ReplicateFor(cr.Annotations, "apis.kcp.io")
+		cr.Annotations, _ = kcpcorehelper.ReplicateFor(cr.Annotations, r.replicateFor)
 	} else {
-		cr.Annotations, _ = kcpcorehelper.DontReplicateFor(cr.Annotations, "apis.kcp.io")
+		cr.Annotations, _ = kcpcorehelper.DontReplicateFor(cr.Annotations, r.replicateFor)
 	}
 
 	return false, nil
@@ -0,0 +1,261 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicationclusterrole
+
+import (
+	"context"
+	"testing"
+
+	kcpkubernetesinformers "github.com/kcp-dev/client-go/informers"
+	kcpfakeclient "github.com/kcp-dev/client-go/kubernetes/fake"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kcp-dev/kcp/pkg/apis/core"
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	kcpfakeclientset "github.com/kcp-dev/kcp/pkg/client/clientset/versioned/cluster/fake"
+	kcpinformers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions"
+)
+
+func TestReconcile(t *testing.T) {
+	bindRule := rbacv1.PolicyRule{APIGroups: []string{"apis.kcp.io"}, Resources: []string{"apiexports"}, Verbs: []string{"bind"}}
+
+	tests := map[string]struct {
+		replicateFor string
+		rules        []rbacv1.PolicyRule
+		annotations  map[string]string
+		want         string
+	}{
+		"bind rule is replicated": {
+			replicateFor: DefaultReplicateFor,
+			rules:        []rbacv1.PolicyRule{bindRule},
+			want:         DefaultReplicateFor,
+		},
+		"custom replication value": {
+			replicateFor: "example.io",
+			rules:        []rbacv1.PolicyRule{bindRule},
+			want:         "example.io",
+		},
+		"custom replication value is added to existing ones": {
+			replicateFor: "example.io",
+			rules:        []rbacv1.PolicyRule{bindRule},
+			annotations:  map[string]string{core.ReplicateAnnotationKey: DefaultReplicateFor},
+			want:         DefaultReplicateFor + ",example.io",
+		},
+		"custom replication value is removed without touching other ones": {
+			replicateFor: "example.io",
+			annotations:  map[string]string{core.ReplicateAnnotationKey: DefaultReplicateFor + ",example.io"},
+			want:         DefaultReplicateFor,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cr := &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "role",
+					Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+				},
+				Rules: tc.rules,
+			}
+			for k, v := range tc.annotations {
+				cr.Annotations[k] = v
+			}
+
+			r := &reconciler{
+				replicateFor: tc.replicateFor,
+				getReferencingClusterRoleBindings: func(cluster logicalcluster.Name, name string) ([]*rbacv1.ClusterRoleBinding, error) {
+					return nil, nil
+				},
+			}
+			_, err := r.reconcile(context.Background(), cr)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, cr.Annotations[core.ReplicateAnnotationKey])
+		})
+	}
+}
+
+func TestNewControllerValidatesReplicateFor(t *testing.T) {
+	tests := map[string]struct {
+		replicateFor string
+		want         string
+		wantErr      bool
+	}{
+		"defaulted":        {replicateFor: "", want: DefaultReplicateFor},
+		"custom":           {replicateFor: "example.io", want: "example.io"},
+		"invalid":          {replicateFor: "not a key", wantErr: true},
+		"invalid prefixed": {replicateFor: "Example_IO/foo", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := kcpfakeclient.NewSimpleClientset()
+			informers := kcpkubernetesinformers.NewSharedInformerFactory(client, 0)
+			kcpInformers := kcpinformers.NewSharedInformerFactory(kcpfakeclientset.NewSimpleClientset(), 0)
+
+			c, err := NewController(client, informers.Rbac().V1().ClusterRoles(), informers.Rbac().V1().ClusterRoleBindings(), kcpInformers.Core().V1alpha1().LogicalClusters(), tc.replicateFor, nil)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, c.replicateFor)
+		})
+	}
+}
+
+func TestWorkspaceSelector(t *testing.T) {
+	client := kcpfakeclient.NewSimpleClientset()
+	informers := kcpkubernetesinformers.NewSharedInformerFactory(client, 0)
+	kcpInformers := kcpinformers.NewSharedInformerFactory(kcpfakeclientset.NewSimpleClientset(), 0)
+	logicalClusterInformer := kcpInformers.Core().V1alpha1().LogicalClusters()
+	clusterRoleInformer := informers.Rbac().V1().ClusterRoles()
+
+	selector, err := labels.Parse("replicate=true")
+	require.NoError(t, err)
+	c, err := NewController(client, clusterRoleInformer, informers.Rbac().V1().ClusterRoleBindings(), logicalClusterInformer, "", selector)
+	require.NoError(t, err)
+	defer c.queue.ShutDown()
+
+	newLogicalCluster := func(clusterName string, labels map[string]string) *corev1alpha1.LogicalCluster {
+		return &corev1alpha1.LogicalCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        corev1alpha1.LogicalClusterName,
+				Labels:      labels,
+				Annotations: map[string]string{logicalcluster.AnnotationKey: clusterName},
+			},
+		}
+	}
+	selected := newLogicalCluster("root:selected", map[string]string{"replicate": "true"})
+	other := newLogicalCluster("root:other", nil)
+	for _, obj := range []*corev1alpha1.LogicalCluster{selected, other} {
+		require.NoError(t, logicalClusterInformer.Informer().GetIndexer().Add(obj))
+	}
+
+	var roles []*rbacv1.ClusterRole
+	for _, clusterName := range []string{"root:selected", "root:other"} {
+		cr := &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "role",
+				Annotations: map[string]string{logicalcluster.AnnotationKey: clusterName},
+			},
+		}
+		require.NoError(t, clusterRoleInformer.Informer().GetIndexer().Add(cr))
+		roles = append(roles, cr)
+	}
+
+	drain := func() []string {
+		var keys []string
+		for c.queue.Len() > 0 {
+			k, _ := c.queue.Get()
+			keys = append(keys, k.(string))
+			c.queue.Done(k)
+			c.queue.Forget(k)
+		}
+		return keys
+	}
+
+	for _, cr := range roles {
+		c.enqueueClusterRole(cr)
+	}
+	require.Equal(t, []string{"root:selected|role"}, drain())
+
+	// unrelated updates of a selected LogicalCluster do not enqueue anything
+	c.enqueueLogicalCluster(selected, selected)
+	require.Empty(t, drain())
+
+	// the other logical cluster starts matching
+	updated := other.DeepCopy()
+	updated.Labels = map[string]string{"replicate": "true"}
+	require.NoError(t, logicalClusterInformer.Informer().GetIndexer().Update(updated))
+	c.enqueueLogicalCluster(other, updated)
+	require.Equal(t, []string{"root:other|role"}, drain())
+
+	// the selected logical cluster stops matching, only its ClusterRoles marked for replication are enqueued
+	marked := roles[0].DeepCopy()
+	marked.Annotations[core.ReplicateAnnotationKey] = DefaultReplicateFor
+	marked.Rules = []rbacv1.PolicyRule{{APIGroups: []string{"apis.kcp.io"}, Resources: []string{"apiexports"}, Verbs: []string{"bind"}}}
+	require.NoError(t, clusterRoleInformer.Informer().GetIndexer().Update(marked))
+	require.NoError(t, clusterRoleInformer.Informer().GetIndexer().Add(&rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "unmarked",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:selected"},
+		},
+	}))
+	deselected := selected.DeepCopy()
+	deselected.Labels = nil
+	require.NoError(t, logicalClusterInformer.Informer().GetIndexer().Update(deselected))
+	c.enqueueLogicalCluster(selected, deselected)
+	require.Equal(t, []string{"root:selected|role"}, drain())
+
+	// and are no longer marked for replication, despite their bind rule
+	var committed *rbacv1.ClusterRole
+	c.commit = func(ctx context.Context, old, new *rbacv1.ClusterRole) error {
+		committed = new
+		return nil
+	}
+	c.queue.Add("root:selected|role")
+	require.True(t, c.processNextWorkItem(context.Background()))
+	require.NotNil(t, committed)
+	require.NotContains(t, committed.Annotations, core.ReplicateAnnotationKey)
+}
+
+func TestSkipDeletingLogicalCluster(t *testing.T) {
+	client := kcpfakeclient.NewSimpleClientset()
+	informers := kcpkubernetesinformers.NewSharedInformerFactory(client, 0)
+	kcpInformers := kcpinformers.NewSharedInformerFactory(kcpfakeclientset.NewSimpleClientset(), 0)
+	logicalClusterInformer := kcpInformers.Core().V1alpha1().LogicalClusters()
+	clusterRoleInformer := informers.Rbac().V1().ClusterRoles()
+
+	c, err := NewController(client, clusterRoleInformer, informers.Rbac().V1().ClusterRoleBindings(), logicalClusterInformer, "", nil)
+	require.NoError(t, err)
+	defer c.queue.ShutDown()
+
+	var committed []string
+	c.commit = func(ctx context.Context, old, new *rbacv1.ClusterRole) error {
+		committed = append(committed, logicalcluster.From(new).String())
+		return nil
+	}
+
+	now := metav1.Now()
+	for clusterName, deletionTimestamp := range map[string]*metav1.Time{"root:deleting": &now, "root:active": nil} {
+		require.NoError(t, logicalClusterInformer.Informer().GetIndexer().Add(&corev1alpha1.LogicalCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              corev1alpha1.LogicalClusterName,
+				DeletionTimestamp: deletionTimestamp,
+				Annotations:       map[string]string{logicalcluster.AnnotationKey: clusterName},
+			},
+		}))
+		require.NoError(t, clusterRoleInformer.Informer().GetIndexer().Add(&rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "role",
+				Annotations: map[string]string{logicalcluster.AnnotationKey: clusterName},
+			},
+		}))
+	}
+
+	for _, key := range []string{"root:deleting|role", "root:active|role"} {
+		c.queue.AddRateLimited(key)
+		require.True(t, c.processNextWorkItem(context.Background()))
+		require.Zero(t, c.queue.NumRequeues(key), "key %s must be forgotten", key)
+	}
+	require.Equal(t, []string{"root:active"}, committed)
+}
@@ -47,15 +47,24 @@ const (
 )
 
 // NewController returns a new controller for labelling ClusterRoleBinding that should be replicated.
+// They are marked for replication with the replicateFor value, which defaults to
+// replicationclusterrole.DefaultReplicateFor if empty.
 func NewController(
 	kubeClusterClient kcpkubernetesclientset.ClusterInterface,
 	clusterRoleBindingInformer kcprbacinformers.ClusterRoleBindingClusterInformer,
 	clusterRoleInformer kcprbacinformers.ClusterRoleClusterInformer,
+	replicateFor string,
 ) (*controller, error) {
+	replicateFor, err := replicationclusterrole.ReplicateForOrDefault(replicateFor)
+	if err != nil {
+		return nil, err
+	}
+
 	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
 
 	c := &controller{
-		queue: queue,
+		queue:        queue,
+		replicateFor: replicateFor,
 
 		kubeClusterClient: kubeClusterClient,
 
@@ -65,7 +74,7 @@ func NewController(
 		clusterRoleLister:  clusterRoleInformer.Lister(),
 		clusterRoleIndexer: clusterRoleInformer.Informer().GetIndexer(),
 
-		commit: committer.NewStatuslessCommitter[*rbacv1.ClusterRoleBinding, rbacclientv1.ClusterRoleBindingInterface](kubeClusterClient.RbacV1().ClusterRoleBindings(), committer.ShallowCopy[rbacv1.ClusterRoleBinding]),
+		commit: committer.NewStatuslessCommitter[*rbacv1.ClusterRoleBinding, rbacclientv1.ClusterRoleBindingInterface](kubeClusterClient.RbacV1().ClusterRoleBindings(), committer.ShallowCopy[rbacv1.ClusterRoleBinding], committer.WithFieldManager(ControllerName)),
 	}
 
 	indexers.AddIfNotPresentOrDie(clusterRoleBindingInformer.Informer().GetIndexer(), cache.Indexers{
@@ -105,6 +114,9 @@ func NewController(
 type controller struct {
 	queue workqueue.RateLimitingInterface
 
+	// replicateFor is the value ClusterRoleBindings are marked for replication with.
+	replicateFor string
+
 	kubeClusterClient kcpkubernetesclientset.ClusterInterface
 
 	clusterRoleBindingLister  kcprbaclisters.ClusterRoleBindingClusterLister
@@ -20,6 +20,7 @@ import (
 	"context"
 	"strings"
 
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
 	"github.com/kcp-dev/logicalcluster/v3"
 
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -27,20 +28,28 @@ import (
 
 	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
 	kcpcorehelper "github.com/kcp-dev/kcp/pkg/apis/core/helper"
+	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/replicationclusterrole"
 )
 
 func (c *controller) reconcile(ctx context.Context, crb *rbacv1.ClusterRoleBinding) (bool, error) {
 	r := &reconciler{
+		replicateFor: c.replicateFor,
 		getClusterRole: func(cluster logicalcluster.Name, name string) (*rbacv1.ClusterRole, error) {
 			return c.clusterRoleLister.Cluster(cluster).Get(name)
 		},
+		getReferencingClusterRoleBindings: func(cluster logicalcluster.Name, name string) ([]*rbacv1.ClusterRoleBinding, error) {
+			key := kcpcache.ToClusterAwareKey(cluster.String(), "", name)
+			return indexers.ByIndex[*rbacv1.ClusterRoleBinding](c.clusterRoleBindingIndexer, replicationclusterrole.ClusterRoleBindingByClusterRoleName, key)
+		},
 	}
 	return r.reconcile(ctx, crb)
 }
 
 type reconciler struct {
-	getClusterRole func(cluster logicalcluster.Name, name string) (*rbacv1.ClusterRole, error)
+	replicateFor                      string
+	getClusterRole                    func(cluster logicalcluster.Name, name string) (*rbacv1.ClusterRole, error)
+	getReferencingClusterRoleBindings func(cluster logicalcluster.Name, name string) ([]*rbacv1.ClusterRoleBinding, error)
 }
 
 func (r *reconciler) reconcile(ctx context.Context, crb *rbacv1.ClusterRoleBinding) (bool, error) {
@@ -62,13 +71,27 @@ func (r *reconciler) reconcile(ctx context.Context, crb *rbacv1.ClusterRoleBindi
 		if cr != nil && replicationclusterrole.HasBindOrContentRule(cr) {
 			replicate = true
 		}
+
+		// references a ClusterRole that is replicated because it is bound to a maximum-permission-policy subject?
+		if !replicate && cr != nil {
+			crbs, err := r.getReferencingClusterRoleBindings(logicalcluster.From(cr), cr.Name)
+			if err != nil {
+				return false, err
+			}
+			for _, other := range crbs {
+				if replicationclusterrole.HasMaximalPermissionClaimSubject(other) {
+					replicate = true
+					break
+				}
+			}
+		}
 	}
 
 	// calculate patch
 	if replicate {
-		crb.Annotations, _ = kcpcorehelper.ReplicateFor(crb.Annotations, "apis.kcp.io")
+		crb.Annotations, _ = kcpcorehelper.ReplicateFor(crb.Annotations, r.replicateFor)
 	} else {
-		crb.Annotations, _ = kcpcorehelper.DontReplicateFor(crb.Annotations, "apis.kcp.io")
+		crb.Annotations, _ = kcpcorehelper.DontReplicateFor(crb.Annotations, r.replicateFor)
 	}
 
 	return false, nil
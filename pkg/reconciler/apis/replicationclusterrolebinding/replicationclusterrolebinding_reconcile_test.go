@@ -0,0 +1,142 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicationclusterrolebinding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/core"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/replicationclusterrole"
+)
+
+func TestReconcile(t *testing.T) {
+	clusterRole := func(name string, rules ...rbacv1.PolicyRule) *rbacv1.ClusterRole {
+		return &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+			},
+			Rules: rules,
+		}
+	}
+	clusterRoleBinding := func(name, roleName string, subjects ...rbacv1.Subject) *rbacv1.ClusterRoleBinding {
+		return &rbacv1.ClusterRoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+			},
+			RoleRef:  rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: roleName},
+			Subjects: subjects,
+		}
+	}
+	maximalPermissionPolicySubject := rbacv1.Subject{
+		APIGroup: rbacv1.GroupName,
+		Kind:     rbacv1.UserKind,
+		Name:     apisv1alpha1.MaximalPermissionPolicyRBACUserGroupPrefix + "alice",
+	}
+	userSubject := rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: "bob"}
+	bindRule := rbacv1.PolicyRule{APIGroups: []string{"apis.kcp.io"}, Resources: []string{"apiexports"}, Verbs: []string{"bind"}}
+
+	tests := map[string]struct {
+		crb            *rbacv1.ClusterRoleBinding
+		clusterRoles   []*rbacv1.ClusterRole
+		siblings       []*rbacv1.ClusterRoleBinding
+		replicateFor   string
+		wantReplicated bool
+	}{
+		"maximum-permission-policy subject": {
+			crb:            clusterRoleBinding("mpp", "role", maximalPermissionPolicySubject),
+			wantReplicated: true,
+		},
+		"maximum-permission-policy subject with custom replication value": {
+			crb:            clusterRoleBinding("mpp", "role", maximalPermissionPolicySubject),
+			replicateFor:   "example.io",
+			wantReplicated: true,
+		},
+		"references ClusterRole with bind rule": {
+			crb:            clusterRoleBinding("binder", "role", userSubject),
+			clusterRoles:   []*rbacv1.ClusterRole{clusterRole("role", bindRule)},
+			wantReplicated: true,
+		},
+		"references ClusterRole bound to a maximum-permission-policy subject": {
+			crb:          clusterRoleBinding("user", "role", userSubject),
+			clusterRoles: []*rbacv1.ClusterRole{clusterRole("role")},
+			siblings: []*rbacv1.ClusterRoleBinding{
+				clusterRoleBinding("user", "role", userSubject),
+				clusterRoleBinding("mpp", "role", maximalPermissionPolicySubject),
+			},
+			wantReplicated: true,
+		},
+		"references unrelated ClusterRole": {
+			crb:          clusterRoleBinding("user", "role", userSubject),
+			clusterRoles: []*rbacv1.ClusterRole{clusterRole("role")},
+			siblings: []*rbacv1.ClusterRoleBinding{
+				clusterRoleBinding("user", "role", userSubject),
+			},
+		},
+		"replication is removed when the ClusterRole is no longer replicated": {
+			crb: func() *rbacv1.ClusterRoleBinding {
+				crb := clusterRoleBinding("user", "role", userSubject)
+				crb.Annotations[core.ReplicateAnnotationKey] = "apis.kcp.io"
+				return crb
+			}(),
+			clusterRoles: []*rbacv1.ClusterRole{clusterRole("role")},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			replicateFor := tc.replicateFor
+			if replicateFor == "" {
+				replicateFor = replicationclusterrole.DefaultReplicateFor
+			}
+			r := &reconciler{
+				replicateFor: replicateFor,
+				getClusterRole: func(cluster logicalcluster.Name, name string) (*rbacv1.ClusterRole, error) {
+					for _, cr := range tc.clusterRoles {
+						if cr.Name == name {
+							return cr, nil
+						}
+					}
+					return nil, apierrors.NewNotFound(rbacv1.Resource("clusterroles"), name)
+				},
+				getReferencingClusterRoleBindings: func(cluster logicalcluster.Name, name string) ([]*rbacv1.ClusterRoleBinding, error) {
+					return tc.siblings, nil
+				},
+			}
+
+			requeue, err := r.reconcile(context.Background(), tc.crb)
+			require.NoError(t, err)
+			require.False(t, requeue)
+
+			value, replicated := tc.crb.Annotations[core.ReplicateAnnotationKey]
+			require.Equal(t, tc.wantReplicated, replicated)
+			if tc.wantReplicated {
+				require.Equal(t, replicateFor, value)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicationrole
+
+import (
+	"context"
+	"strings"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	kcpcorehelper "github.com/kcp-dev/kcp/pkg/apis/core/helper"
+	"github.com/kcp-dev/kcp/pkg/indexers"
+)
+
+func (c *controller) reconcile(ctx context.Context, role *rbacv1.Role) (bool, error) {
+	r := &reconciler{
+		replicateFor: c.replicateFor,
+		getReferencingRoleBindings: func(cluster logicalcluster.Name, namespace, name string) ([]*rbacv1.RoleBinding, error) {
+			key := kcpcache.ToClusterAwareKey(cluster.String(), namespace, name)
+			return indexers.ByIndex[*rbacv1.RoleBinding](c.roleBindingIndexer, RoleBindingByRoleName, key)
+		},
+	}
+	return r.reconcile(ctx, role)
+}
+
+type reconciler struct {
+	replicateFor               string
+	getReferencingRoleBindings func(cluster logicalcluster.Name, namespace, name string) ([]*rbacv1.RoleBinding, error)
+}
+
+func (r *reconciler) reconcile(ctx context.Context, role *rbacv1.Role) (bool, error) {
+	replicate := false
+	objs, err := r.getReferencingRoleBindings(logicalcluster.From(role), role.Namespace, role.Name)
+	if err != nil {
+		runtime.HandleError(err)
+		return false, nil // nothing we can do
+	}
+	for _, rb := range objs {
+		if HasMaximalPermissionClaimSubject(rb) {
+			replicate = true
+			break
+		}
+	}
+
+	if replicate {
+		role.Annotations, _ = kcpcorehelper.ReplicateFor(role.Annotations, r.replicateFor)
+	} else {
+		role.Annotations, _ = kcpcorehelper.DontReplicateFor(role.Annotations, r.replicateFor)
+	}
+
+	return false, nil
+}
+
+func HasMaximalPermissionClaimSubject(rb *rbacv1.RoleBinding) bool {
+	for _, s := range rb.Subjects {
+		if strings.HasPrefix(s.Name, apisv1alpha1.MaximalPermissionPolicyRBACUserGroupPrefix) && (s.Kind == rbacv1.UserKind || s.Kind == rbacv1.GroupKind) && s.APIGroup == rbacv1.GroupName {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicationrole
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/core"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/replicationclusterrole"
+)
+
+func TestReconcile(t *testing.T) {
+	role := func(namespace, name string) *rbacv1.Role {
+		return &rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   namespace,
+				Name:        name,
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+			},
+		}
+	}
+	roleBinding := func(namespace, name, roleName string, subjects ...rbacv1.Subject) *rbacv1.RoleBinding {
+		return &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   namespace,
+				Name:        name,
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+			},
+			RoleRef:  rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: roleName},
+			Subjects: subjects,
+		}
+	}
+	maximalPermissionPolicySubject := rbacv1.Subject{
+		APIGroup: rbacv1.GroupName,
+		Kind:     rbacv1.GroupKind,
+		Name:     apisv1alpha1.MaximalPermissionPolicyRBACUserGroupPrefix + "authenticated",
+	}
+	userSubject := rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: "bob"}
+
+	tests := map[string]struct {
+		role           *rbacv1.Role
+		roleBindings   []*rbacv1.RoleBinding
+		replicateFor   string
+		wantReplicated bool
+	}{
+		"Role bound to maximum-permission-policy subject": {
+			role: role("ns", "policy"),
+			roleBindings: []*rbacv1.RoleBinding{
+				roleBinding("ns", "mpp", "policy", maximalPermissionPolicySubject),
+			},
+			wantReplicated: true,
+		},
+		"Role bound to maximum-permission-policy subject with custom replication value": {
+			role: role("ns", "policy"),
+			roleBindings: []*rbacv1.RoleBinding{
+				roleBinding("ns", "mpp", "policy", maximalPermissionPolicySubject),
+			},
+			replicateFor:   "example.io",
+			wantReplicated: true,
+		},
+		"Role bound to maximum-permission-policy subject in another namespace": {
+			role: role("ns", "policy"),
+			roleBindings: []*rbacv1.RoleBinding{
+				roleBinding("other", "mpp", "policy", maximalPermissionPolicySubject),
+			},
+		},
+		"Role bound to a user only": {
+			role: role("ns", "policy"),
+			roleBindings: []*rbacv1.RoleBinding{
+				roleBinding("ns", "user", "policy", userSubject),
+			},
+		},
+		"Role not bound anymore": {
+			role: func() *rbacv1.Role {
+				r := role("ns", "policy")
+				r.Annotations[core.ReplicateAnnotationKey] = "apis.kcp.io"
+				return r
+			}(),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{
+				RoleBindingByRoleName: IndexRoleBindingByRoleName,
+			})
+			for _, rb := range tc.roleBindings {
+				require.NoError(t, indexer.Add(rb))
+			}
+
+			replicateFor := tc.replicateFor
+			if replicateFor == "" {
+				replicateFor = replicationclusterrole.DefaultReplicateFor
+			}
+			c := &controller{roleBindingIndexer: indexer, replicateFor: replicateFor}
+			requeue, err := c.reconcile(context.Background(), tc.role)
+			require.NoError(t, err)
+			require.False(t, requeue)
+
+			value, replicated := tc.role.Annotations[core.ReplicateAnnotationKey]
+			require.Equal(t, tc.wantReplicated, replicated)
+			if tc.wantReplicated {
+				require.Equal(t, replicateFor, value)
+			}
+		})
+	}
+}
+
+func TestIndexRoleBindingByRoleName(t *testing.T) {
+	rb := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "ns",
+			Name:        "binding",
+			Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+		},
+		RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "policy"},
+	}
+	keys, err := IndexRoleBindingByRoleName(rb)
+	require.NoError(t, err)
+	require.Equal(t, []string{"root:org|ns/policy"}, keys)
+
+	rb.RoleRef.Kind = "ClusterRole"
+	keys, err = IndexRoleBindingByRoleName(rb)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+}
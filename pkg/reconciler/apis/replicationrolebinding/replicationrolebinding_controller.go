@@ -0,0 +1,206 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicationrolebinding
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
+	kcprbacinformers "github.com/kcp-dev/client-go/informers/rbac/v1"
+	kcpkubernetesclientset "github.com/kcp-dev/client-go/kubernetes"
+	kcprbaclisters "github.com/kcp-dev/client-go/listers/rbac/v1"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/kcp-dev/kcp/pkg/logging"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/replicationclusterrole"
+	"github.com/kcp-dev/kcp/pkg/reconciler/committer"
+)
+
+const (
+	ControllerName = "kcp-apis-replication-rolebinding"
+)
+
+// NewController returns a new controller for labelling RoleBindings that should be replicated.
+// They are marked for replication with the replicateFor value, which defaults to
+// replicationclusterrole.DefaultReplicateFor if empty.
+func NewController(
+	kubeClusterClient kcpkubernetesclientset.ClusterInterface,
+	roleBindingInformer kcprbacinformers.RoleBindingClusterInformer,
+	replicateFor string,
+) (*controller, error) {
+	replicateFor, err := replicationclusterrole.ReplicateForOrDefault(replicateFor)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), ControllerName)
+
+	c := &controller{
+		queue:        queue,
+		replicateFor: replicateFor,
+
+		kubeClusterClient: kubeClusterClient,
+
+		roleBindingLister: roleBindingInformer.Lister(),
+	}
+	c.commit = func(ctx context.Context, old, obj *rbacv1.RoleBinding) error {
+		patcher := kubeClusterClient.RbacV1().RoleBindings().Cluster(logicalcluster.From(old).Path()).Namespace(old.Namespace)
+		return committer.NewStatuslessCommitterScoped[*rbacv1.RoleBinding](patcher, committer.ShallowCopy[rbacv1.RoleBinding], committer.WithFieldManager(ControllerName))(ctx, old, obj)
+	}
+
+	roleBindingInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueRoleBinding(obj)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueRoleBinding(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueRoleBinding(obj)
+		},
+	})
+
+	return c, nil
+}
+
+// controller reconciles RoleBindings by labelling them to be replicated when
+// a maximum-permission-policy subject is bound. The maximal permission policy
+// authorizer reads the replicated RoleBindings of an APIExport's logical cluster
+// from the cache server when the APIExport lives on another shard.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	// replicateFor is the value RoleBindings are marked for replication with.
+	replicateFor string
+
+	kubeClusterClient kcpkubernetesclientset.ClusterInterface
+
+	roleBindingLister kcprbaclisters.RoleBindingClusterLister
+
+	// commit creates a patch and submits it, if needed.
+	commit func(ctx context.Context, old, new *rbacv1.RoleBinding) error
+}
+
+// enqueueRoleBinding enqueues a RoleBinding.
+func (c *controller) enqueueRoleBinding(obj interface{}) {
+	key, err := kcpcache.DeletionHandlingMetaClusterNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger := logging.WithQueueKey(logging.WithReconciler(klog.Background(), ControllerName), key)
+	logger.V(4).Info("queueing RoleBinding")
+	c.queue.Add(key)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := logging.WithReconciler(klog.FromContext(ctx), ControllerName)
+	ctx = klog.NewContext(ctx, logger)
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := logging.WithQueueKey(klog.FromContext(ctx), key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(4).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if requeue, err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", ControllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	} else if requeue {
+		// only requeue if we didn't error, but we still want to requeue
+		c.queue.Add(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) (bool, error) {
+	cluster, namespace, name, err := kcpcache.SplitMetaClusterNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return false, nil
+	}
+
+	rb, err := c.roleBindingLister.Cluster(cluster).RoleBindings(namespace).Get(name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil // object deleted before we handled it
+		}
+		return false, err
+	}
+
+	logger := logging.WithObject(klog.FromContext(ctx), rb)
+	ctx = klog.NewContext(ctx, logger)
+
+	old := rb
+	rb = rb.DeepCopy()
+
+	var errs []error
+	requeue, err := c.reconcile(ctx, rb)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	// If the object being reconciled changed as a result, update it.
+	if err := c.commit(ctx, old, rb); err != nil {
+		errs = append(errs, err)
+	}
+
+	return requeue, utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,37 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicationrolebinding
+
+import (
+	"context"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+
+	kcpcorehelper "github.com/kcp-dev/kcp/pkg/apis/core/helper"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/replicationrole"
+)
+
+func (c *controller) reconcile(ctx context.Context, rb *rbacv1.RoleBinding) (bool, error) {
+	// is a maximum-permission-policy subject?
+	if replicationrole.HasMaximalPermissionClaimSubject(rb) {
+		rb.Annotations, _ = kcpcorehelper.ReplicateFor(rb.Annotations, c.replicateFor)
+	} else {
+		rb.Annotations, _ = kcpcorehelper.DontReplicateFor(rb.Annotations, c.replicateFor)
+	}
+
+	return false, nil
+}
@@ -0,0 +1,117 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package replicationrolebinding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+	"github.com/kcp-dev/kcp/pkg/apis/core"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/replicationclusterrole"
+)
+
+func TestReconcile(t *testing.T) {
+	roleBinding := func(annotations map[string]string, subjects ...rbacv1.Subject) *rbacv1.RoleBinding {
+		rb := &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   "ns",
+				Name:        "binding",
+				Annotations: map[string]string{logicalcluster.AnnotationKey: "root:org"},
+			},
+			RoleRef:  rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: "policy"},
+			Subjects: subjects,
+		}
+		for k, v := range annotations {
+			rb.Annotations[k] = v
+		}
+		return rb
+	}
+	maximalPermissionPolicyGroup := rbacv1.Subject{
+		APIGroup: rbacv1.GroupName,
+		Kind:     rbacv1.GroupKind,
+		Name:     apisv1alpha1.MaximalPermissionPolicyRBACUserGroupPrefix + "authenticated",
+	}
+	maximalPermissionPolicyUser := rbacv1.Subject{
+		APIGroup: rbacv1.GroupName,
+		Kind:     rbacv1.UserKind,
+		Name:     apisv1alpha1.MaximalPermissionPolicyRBACUserGroupPrefix + "alice",
+	}
+	maximalPermissionPolicyServiceAccount := rbacv1.Subject{
+		Kind:      rbacv1.ServiceAccountKind,
+		Namespace: "ns",
+		Name:      apisv1alpha1.MaximalPermissionPolicyRBACUserGroupPrefix + "sa",
+	}
+	userSubject := rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: "bob"}
+
+	tests := map[string]struct {
+		rb           *rbacv1.RoleBinding
+		replicateFor string
+		want         string
+	}{
+		"maximum-permission-policy group": {
+			rb:   roleBinding(nil, userSubject, maximalPermissionPolicyGroup),
+			want: replicationclusterrole.DefaultReplicateFor,
+		},
+		"maximum-permission-policy user": {
+			rb:   roleBinding(nil, maximalPermissionPolicyUser),
+			want: replicationclusterrole.DefaultReplicateFor,
+		},
+		"maximum-permission-policy user with custom replication value": {
+			rb:           roleBinding(nil, maximalPermissionPolicyUser),
+			replicateFor: "example.io",
+			want:         "example.io",
+		},
+		"maximum-permission-policy user already replicated for another value": {
+			rb:   roleBinding(map[string]string{core.ReplicateAnnotationKey: "example.io"}, maximalPermissionPolicyUser),
+			want: replicationclusterrole.DefaultReplicateFor + ",example.io",
+		},
+		"service account with maximum-permission-policy prefix": {
+			rb: roleBinding(nil, maximalPermissionPolicyServiceAccount),
+		},
+		"user only": {
+			rb: roleBinding(nil, userSubject),
+		},
+		"maximum-permission-policy subject removed": {
+			rb: roleBinding(map[string]string{core.ReplicateAnnotationKey: replicationclusterrole.DefaultReplicateFor}, userSubject),
+		},
+		"maximum-permission-policy subject removed keeps other values": {
+			rb:   roleBinding(map[string]string{core.ReplicateAnnotationKey: replicationclusterrole.DefaultReplicateFor + ",example.io"}, userSubject),
+			want: "example.io",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			replicateFor := tc.replicateFor
+			if replicateFor == "" {
+				replicateFor = replicationclusterrole.DefaultReplicateFor
+			}
+			c := &controller{replicateFor: replicateFor}
+			requeue, err := c.reconcile(context.Background(), tc.rb)
+			require.NoError(t, err)
+			require.False(t, requeue)
+			require.Equal(t, tc.want, tc.rb.Annotations[core.ReplicateAnnotationKey])
+		})
+	}
+}
@@ -110,6 +110,22 @@ func NewController(
 				local:  localKubeInformers.Rbac().V1().ClusterRoleBindings().Informer(),
 				global: globalKubeInformers.Rbac().V1().ClusterRoleBindings().Informer(),
 			},
+			rbacv1.SchemeGroupVersion.WithResource("roles"): {
+				kind: "Role",
+				filter: func(u *unstructured.Unstructured) bool {
+					return u.GetAnnotations()[core.ReplicateAnnotationKey] != ""
+				},
+				local:  localKubeInformers.Rbac().V1().Roles().Informer(),
+				global: globalKubeInformers.Rbac().V1().Roles().Informer(),
+			},
+			rbacv1.SchemeGroupVersion.WithResource("rolebindings"): {
+				kind: "RoleBinding",
+				filter: func(u *unstructured.Unstructured) bool {
+					return u.GetAnnotations()[core.ReplicateAnnotationKey] != ""
+				},
+				local:  localKubeInformers.Rbac().V1().RoleBindings().Informer(),
+				global: globalKubeInformers.Rbac().V1().RoleBindings().Informer(),
+			},
 		},
 	}
 
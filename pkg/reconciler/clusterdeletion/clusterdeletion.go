@@ -0,0 +1,55 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clusterdeletion helps controllers to skip objects in logical clusters that are being
+// deleted. Reconciling them only produces noise and conflicts with the deletion of their contents.
+package clusterdeletion
+
+import (
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+	corev1alpha1informers "github.com/kcp-dev/kcp/pkg/client/informers/externalversions/core/v1alpha1"
+)
+
+// IsDeletingFunc returns whether the logical cluster with the given name is being deleted.
+type IsDeletingFunc func(clusterName logicalcluster.Name) bool
+
+// NewIsDeletingFunc returns an IsDeletingFunc that looks up the LogicalCluster of a logical cluster
+// with getLogicalCluster. Logical clusters whose LogicalCluster is unknown are not considered to
+// be deleting, i.e. their objects are reconciled as before.
+func NewIsDeletingFunc(getLogicalCluster func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error)) IsDeletingFunc {
+	return func(clusterName logicalcluster.Name) bool {
+		logicalCluster, err := getLogicalCluster(clusterName)
+		if err != nil {
+			if !errors.IsNotFound(err) {
+				runtime.HandleError(err)
+			}
+			return false
+		}
+		return !logicalCluster.DeletionTimestamp.IsZero()
+	}
+}
+
+// NewInformerIsDeletingFunc is NewIsDeletingFunc using the lister of the given informer.
+func NewInformerIsDeletingFunc(logicalClusterInformer corev1alpha1informers.LogicalClusterClusterInformer) IsDeletingFunc {
+	return NewIsDeletingFunc(func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error) {
+		return logicalClusterInformer.Lister().Cluster(clusterName).Get(corev1alpha1.LogicalClusterName)
+	})
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterdeletion
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	corev1alpha1 "github.com/kcp-dev/kcp/pkg/apis/core/v1alpha1"
+)
+
+func TestNewIsDeletingFunc(t *testing.T) {
+	now := metav1.Now()
+
+	tests := map[string]struct {
+		logicalCluster *corev1alpha1.LogicalCluster
+		err            error
+		want           bool
+	}{
+		"not deleting": {
+			logicalCluster: &corev1alpha1.LogicalCluster{},
+		},
+		"deleting": {
+			logicalCluster: &corev1alpha1.LogicalCluster{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}},
+			want:           true,
+		},
+		"not found": {
+			err: apierrors.NewNotFound(corev1alpha1.Resource("logicalclusters"), corev1alpha1.LogicalClusterName),
+		},
+		"lookup fails": {
+			err: errors.New("boom"),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			isDeleting := NewIsDeletingFunc(func(clusterName logicalcluster.Name) (*corev1alpha1.LogicalCluster, error) {
+				require.Equal(t, logicalcluster.Name("root:org"), clusterName)
+				return tc.logicalCluster, tc.err
+			})
+			require.Equal(t, tc.want, isDeleting("root:org"))
+		})
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package committer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/typed"
+)
+
+// ApplyConfigurationFunc returns the apply configuration of obj, i.e. apiVersion, kind, name and
+// only those fields of obj the controller owns, e.g. built with k8s.io/client-go/applyconfigurations.
+type ApplyConfigurationFunc[R any] func(obj R) (interface{}, error)
+
+// ApplyCommitterOption configures the committer returned by NewApplyCommitter.
+type ApplyCommitterOption interface {
+	applyToApplyCommitter(*applyCommitterOptions)
+}
+
+type applyCommitterOptions struct {
+	fieldManager string
+	force        bool
+}
+
+type forceConflictsOption struct{}
+
+func (forceConflictsOption) applyToApplyCommitter(o *applyCommitterOptions) {
+	o.force = true
+}
+
+// WithForceConflicts makes the apply committer take over fields owned by other managers instead of
+// failing with a conflict.
+func WithForceConflicts() ApplyCommitterOption {
+	return forceConflictsOption{}
+}
+
+// NewApplyCommitter returns a function that server-side applies the fields of status-less R
+// returned by applyConfiguration. The field manager must be set through WithFieldManager. Nothing
+// is sent if the field manager already owns exactly these fields with these values. Conflicts with
+// other managers fail the commit, unless WithForceConflicts is given.
+func NewApplyCommitter[R StatuslessResource, P Patcher[R]](patcher ClusterPatcher[R, P], applyConfiguration ApplyConfigurationFunc[R], opts ...ApplyCommitterOption) func(context.Context, R, R) error {
+	focusType := fmt.Sprintf("%T", new(R))
+	var options applyCommitterOptions
+	for _, opt := range opts {
+		opt.applyToApplyCommitter(&options)
+	}
+	return func(ctx context.Context, old, obj R) error {
+		logger := klog.FromContext(ctx)
+		clusterName := logicalcluster.From(old)
+
+		if options.fieldManager == "" {
+			return fmt.Errorf("failed to apply %s %s|%s: no field manager configured", focusType, clusterName, old.GetName())
+		}
+
+		desired, err := applyConfiguration(obj)
+		if err != nil {
+			return fmt.Errorf("failed to create apply configuration for %s %s|%s: %w", focusType, clusterName, obj.GetName(), err)
+		}
+		applyBytes, err := json.Marshal(desired)
+		if err != nil {
+			return fmt.Errorf("failed to Marshal apply configuration for %s %s|%s: %w", focusType, clusterName, obj.GetName(), err)
+		}
+
+		applied, err := alreadyApplied(old, applyBytes, options.fieldManager)
+		if err != nil {
+			return fmt.Errorf("failed to compare apply configuration for %s %s|%s: %w", focusType, clusterName, obj.GetName(), err)
+		}
+		if applied {
+			return nil
+		}
+
+		patchOptions := metav1.PatchOptions{FieldManager: options.fieldManager}
+		if options.force {
+			patchOptions.Force = pointer.Bool(true)
+		}
+
+		logger.V(2).Info(fmt.Sprintf("applying %s", focusType), "patch", string(applyBytes), "fieldManager", options.fieldManager)
+		if _, err := patcher.Cluster(clusterName.Path()).Patch(ctx, obj.GetName(), types.ApplyPatchType, applyBytes, patchOptions); err != nil {
+			return fmt.Errorf("failed to apply %s %s|%s: %w", focusType, clusterName, old.GetName(), err)
+		}
+
+		return nil
+	}
+}
+
+// notManagedFields are the fields of an apply configuration the server never records in managedFields.
+var notManagedFields = fieldpath.NewSet(
+	fieldpath.MakePathOrDie("apiVersion"),
+	fieldpath.MakePathOrDie("kind"),
+	fieldpath.MakePathOrDie("metadata", "name"),
+	fieldpath.MakePathOrDie("metadata", "namespace"),
+)
+
+// alreadyApplied returns true if fieldManager's last apply to old owns exactly the fields of the
+// apply configuration, and old holds their values. Types are deduced from the values, i.e. all
+// lists are treated as atomic. For associative lists this errs on the side of applying again.
+func alreadyApplied(old metav1.Object, applyBytes []byte, fieldManager string) (bool, error) {
+	var owned *fieldpath.Set
+	for _, entry := range old.GetManagedFields() {
+		if entry.Manager != fieldManager || entry.Operation != metav1.ManagedFieldsOperationApply || entry.Subresource != "" || entry.FieldsV1 == nil {
+			continue
+		}
+		owned = &fieldpath.Set{}
+		if err := owned.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return false, err
+		}
+	}
+	if owned == nil {
+		return false, nil
+	}
+
+	desired, err := typed.DeducedParseableType.FromYAML(typed.YAMLObject(applyBytes))
+	if err != nil {
+		return false, err
+	}
+	desiredFields, err := desired.ToFieldSet()
+	if err != nil {
+		return false, err
+	}
+	if !desiredFields.Difference(notManagedFields).Leaves().Equals(owned.Leaves()) {
+		return false, nil
+	}
+
+	oldUnstructured, err := runtime.DefaultUnstructuredConverter.ToUnstructured(old)
+	if err != nil {
+		return false, err
+	}
+	current, err := typed.DeducedParseableType.FromUnstructured(oldUnstructured)
+	if err != nil {
+		return false, err
+	}
+	merged, err := current.Merge(desired)
+	if err != nil {
+		return false, err
+	}
+	comparison, err := current.Compare(merged)
+	if err != nil {
+		return false, err
+	}
+	// objects from listers come without apiVersion and kind
+	return comparison.ExcludeFields(notManagedFields).IsSame(), nil
+}
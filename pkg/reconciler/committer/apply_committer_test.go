@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package committer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	rbacv1ac "k8s.io/client-go/applyconfigurations/rbac/v1"
+)
+
+type fakeApplyClient struct {
+	patchTypes []types.PatchType
+	patches    [][]byte
+	opts       []metav1.PatchOptions
+}
+
+func (f *fakeApplyClient) Cluster(cluster logicalcluster.Path) *fakeApplyClient {
+	return f
+}
+
+func (f *fakeApplyClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*rbacv1.ClusterRole, error) {
+	f.patchTypes = append(f.patchTypes, pt)
+	f.patches = append(f.patches, data)
+	f.opts = append(f.opts, opts)
+	return nil, nil
+}
+
+func TestApplyCommitter(t *testing.T) {
+	const replicateKey = "internal.kcp.io/replicate"
+	applyConfiguration := func(r *rbacv1.ClusterRole) (interface{}, error) {
+		return rbacv1ac.ClusterRole(r.Name).WithAnnotations(map[string]string{replicateKey: r.Annotations[replicateKey]}), nil
+	}
+	managedFields := func(manager string, operation metav1.ManagedFieldsOperationType) []metav1.ManagedFieldsEntry {
+		return []metav1.ManagedFieldsEntry{{
+			Manager:    manager,
+			Operation:  operation,
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:metadata":{"f:annotations":{".":{},"f:internal.kcp.io/replicate":{}}}}`)},
+		}}
+	}
+	newRole := func(replicate string, managedFields []metav1.ManagedFieldsEntry) *rbacv1.ClusterRole {
+		return &rbacv1.ClusterRole{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "role",
+				UID:             "uid",
+				ResourceVersion: "1",
+				Annotations:     map[string]string{logicalcluster.AnnotationKey: "root:org", replicateKey: replicate},
+				Labels:          map[string]string{"someone-else": "owns-this"},
+				ManagedFields:   managedFields,
+			},
+			Rules: []rbacv1.PolicyRule{{Verbs: []string{"get"}, APIGroups: []string{""}, Resources: []string{"configmaps"}}},
+		}
+	}
+	wantApply := map[string]interface{}{
+		"apiVersion": "rbac.authorization.k8s.io/v1",
+		"kind":       "ClusterRole",
+		"metadata": map[string]interface{}{
+			"name":        "role",
+			"annotations": map[string]interface{}{replicateKey: "apis.kcp.io"},
+		},
+	}
+
+	tests := map[string]struct {
+		old       *rbacv1.ClusterRole
+		opts      []ApplyCommitterOption
+		wantApply bool
+		wantForce bool
+		wantErr   bool
+	}{
+		"owned field unchanged": {
+			old:  newRole("apis.kcp.io", managedFields("test-controller", metav1.ManagedFieldsOperationApply)),
+			opts: []ApplyCommitterOption{WithFieldManager("test-controller")},
+		},
+		"owned field changed": {
+			old:       newRole("something-else", managedFields("test-controller", metav1.ManagedFieldsOperationApply)),
+			opts:      []ApplyCommitterOption{WithFieldManager("test-controller")},
+			wantApply: true,
+		},
+		"field owned by another manager": {
+			old:       newRole("apis.kcp.io", managedFields("someone-else", metav1.ManagedFieldsOperationApply)),
+			opts:      []ApplyCommitterOption{WithFieldManager("test-controller")},
+			wantApply: true,
+		},
+		"field last updated instead of applied": {
+			old:       newRole("apis.kcp.io", managedFields("test-controller", metav1.ManagedFieldsOperationUpdate)),
+			opts:      []ApplyCommitterOption{WithFieldManager("test-controller")},
+			wantApply: true,
+		},
+		"forcing conflicts": {
+			old:       newRole("something-else", nil),
+			opts:      []ApplyCommitterOption{WithFieldManager("test-controller"), WithForceConflicts()},
+			wantApply: true,
+			wantForce: true,
+		},
+		"no field manager": {
+			old:     newRole("something-else", nil),
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := tc.old.DeepCopy()
+			obj.Annotations[replicateKey] = "apis.kcp.io"
+
+			client := &fakeApplyClient{}
+			commit := NewApplyCommitter[*rbacv1.ClusterRole, *fakeApplyClient](client, applyConfiguration, tc.opts...)
+			err := commit(context.Background(), tc.old, obj)
+			if tc.wantErr {
+				require.Error(t, err)
+				require.Empty(t, client.patches)
+				return
+			}
+			require.NoError(t, err)
+
+			if !tc.wantApply {
+				require.Empty(t, client.patches)
+				return
+			}
+
+			require.Len(t, client.patches, 1)
+			require.Equal(t, types.ApplyPatchType, client.patchTypes[0])
+			require.Equal(t, "test-controller", client.opts[0].FieldManager)
+			if tc.wantForce {
+				require.NotNil(t, client.opts[0].Force)
+				require.True(t, *client.opts[0].Force)
+			} else {
+				require.Nil(t, client.opts[0].Force)
+			}
+
+			var applied map[string]interface{}
+			require.NoError(t, json.Unmarshal(client.patches[0], &applied))
+			require.Equal(t, wantApply, applied, "only the owned fields are applied")
+		})
+	}
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package committer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/klog/v2"
+)
+
+// CommitMode selects the API call a committer uses to persist changes.
+type CommitMode string
+
+const (
+	// MergePatchMode sends JSON merge patches. This is the default.
+	MergePatchMode CommitMode = "MergePatch"
+	// JSONPatchMode sends JSON (RFC 6902) patches.
+	JSONPatchMode CommitMode = "JSONPatch"
+	// StrategicMergePatchMode sends strategic merge patches. Only built-in types support them,
+	// custom resources reject them.
+	StrategicMergePatchMode CommitMode = "StrategicMergePatch"
+	// UpdateMode replaces the whole object with an update. The patcher must implement Updater.
+	UpdateMode CommitMode = "Update"
+)
+
+// Updater is just the Update API with a generic to keep use sites type safe.
+type Updater[R any] interface {
+	Update(ctx context.Context, obj R, opts metav1.UpdateOptions) (R, error)
+}
+
+// WithCommitMode makes the committer persist changes with the given mode instead of JSON merge patches.
+// NewSubresourceCommitter sends the output of its patch function with the patch type of the mode, and
+// does not support UpdateMode.
+func WithCommitMode(mode CommitMode) StatuslessCommitterOption {
+	return mode
+}
+
+func (m CommitMode) applyToStatuslessCommitter(o *statuslessCommitterOptions) {
+	o.mode = m
+}
+
+func (m CommitMode) patchType() types.PatchType {
+	switch m {
+	case "", MergePatchMode:
+		return types.MergePatchType
+	case JSONPatchMode:
+		return types.JSONPatchType
+	case StrategicMergePatchMode:
+		return types.StrategicMergePatchType
+	default:
+		panic(fmt.Sprintf("programmer error: commit mode %q does not patch", m))
+	}
+}
+
+// newUpdateCommitter returns a function that updates instances of status-less R. The uid and
+// resourceVersion of old are sent along, such that the update fails if the object changed meanwhile.
+func newUpdateCommitter[R StatuslessResource](patcherFor func(logicalcluster.Name) Patcher[R], shallowCopy ObjectMetaShallowCopy[R], options statuslessCommitterOptions) func(context.Context, R, R) error {
+	focusType := fmt.Sprintf("%T", new(R))
+	return func(ctx context.Context, old, obj R) error {
+		logger := klog.FromContext(ctx)
+		clusterName := logicalcluster.From(old)
+
+		if unchanged(shallowCopy, old, obj) {
+			return nil
+		}
+
+		updater, ok := patcherFor(clusterName).(Updater[R])
+		if !ok {
+			return fmt.Errorf("failed to update %s %s|%s: client does not support updates", focusType, clusterName, old.GetName())
+		}
+
+		forUpdate := shallowCopy(obj)
+		forUpdate.SetUID(old.GetUID())
+		forUpdate.SetResourceVersion(old.GetResourceVersion())
+
+		logger.V(2).Info(fmt.Sprintf("updating %s", focusType))
+		if _, err := updater.Update(ctx, forUpdate, metav1.UpdateOptions{FieldManager: options.fieldManager}); err != nil {
+			return fmt.Errorf("failed to update %s %s|%s: %w", focusType, clusterName, old.GetName(), err)
+		}
+
+		if options.logDiff && logger.V(4).Enabled() {
+			if patchBytes, err := generateStatusLessPatchAndSubResources(shallowCopy, old, obj); err == nil {
+				logDiff(logger, obj, patchBytes)
+			}
+		}
+
+		return nil
+	}
+}
+
+func generateStatuslessStrategicMergePatch[R StatuslessResource](shallowCopy ObjectMetaShallowCopy[R], old, obj R) ([]byte, error) {
+	if unchanged(shallowCopy, old, obj) {
+		return nil, nil
+	}
+	oldData, newData, err := marshalForPatch(shallowCopy, old, obj)
+	if err != nil {
+		return nil, err
+	}
+	patchBytes, err := strategicpatch.CreateTwoWayMergePatch(oldData, newData, obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create patch for %s|%s: %w", logicalcluster.From(old), old.GetName(), err)
+	}
+	return patchBytes, nil
+}
+
+// generateStatuslessJSONPatch returns the JSON patch from old to obj. Like the merge patch, it sets
+// the uid and resourceVersion of old, which the server treats as preconditions.
+func generateStatuslessJSONPatch[R StatuslessResource](shallowCopy ObjectMetaShallowCopy[R], old, obj R) ([]byte, error) {
+	if unchanged(shallowCopy, old, obj) {
+		return nil, nil
+	}
+	oldData, newData, err := marshalForPatch(shallowCopy, old, obj)
+	if err != nil {
+		return nil, err
+	}
+	patchBytes, err := createJSONPatch(oldData, newData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create patch for %s|%s: %w", logicalcluster.From(old), old.GetName(), err)
+	}
+	return patchBytes, nil
+}
+
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// createJSONPatch translates the merge patch from oldData to newData into JSON patch operations.
+// Like in the merge patch, lists are replaced as a whole.
+func createJSONPatch(oldData, newData []byte) ([]byte, error) {
+	mergePatch, err := jsonpatch.CreateMergePatch(oldData, newData)
+	if err != nil {
+		return nil, err
+	}
+	var patch, oldDoc map[string]interface{}
+	if err := json.Unmarshal(mergePatch, &patch); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(oldData, &oldDoc); err != nil {
+		return nil, err
+	}
+
+	ops, err := jsonPatchOperations("", oldDoc, patch)
+	if err != nil {
+		return nil, err
+	}
+	if len(ops) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(ops)
+}
+
+func jsonPatchOperations(prefix string, old, patch map[string]interface{}) ([]jsonPatchOperation, error) {
+	keys := make([]string, 0, len(patch))
+	for k := range patch {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var ops []jsonPatchOperation
+	for _, k := range keys {
+		path := prefix + "/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(k)
+		oldValue, exists := old[k]
+		v := patch[k]
+
+		if v == nil {
+			ops = append(ops, jsonPatchOperation{Op: "remove", Path: path})
+			continue
+		}
+		nestedPatch, isMap := v.(map[string]interface{})
+		nestedOld, oldIsMap := oldValue.(map[string]interface{})
+		if isMap && oldIsMap {
+			nested, err := jsonPatchOperations(path, nestedOld, nestedPatch)
+			if err != nil {
+				return nil, err
+			}
+			ops = append(ops, nested...)
+			continue
+		}
+
+		op := "add"
+		if exists {
+			op = "replace"
+		}
+		value, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		ops = append(ops, jsonPatchOperation{Op: op, Path: path, Value: value})
+	}
+	return ops, nil
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package committer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type fakeUpdatingClusterRoleClient struct {
+	calls         []string
+	patches       []string
+	updates       []*rbacv1.ClusterRole
+	fieldManagers []string
+}
+
+func (f *fakeUpdatingClusterRoleClient) Cluster(cluster logicalcluster.Path) *fakeUpdatingClusterRoleClient {
+	return f
+}
+
+func (f *fakeUpdatingClusterRoleClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*rbacv1.ClusterRole, error) {
+	f.calls = append(f.calls, string(pt))
+	f.patches = append(f.patches, string(data))
+	f.fieldManagers = append(f.fieldManagers, opts.FieldManager)
+	return nil, nil
+}
+
+func (f *fakeUpdatingClusterRoleClient) Update(ctx context.Context, obj *rbacv1.ClusterRole, opts metav1.UpdateOptions) (*rbacv1.ClusterRole, error) {
+	f.calls = append(f.calls, "update")
+	f.updates = append(f.updates, obj)
+	f.fieldManagers = append(f.fieldManagers, opts.FieldManager)
+	return obj, nil
+}
+
+func TestStatuslessCommitterModes(t *testing.T) {
+	tests := map[string]struct {
+		opts        []StatuslessCommitterOption
+		wantCalls   []string
+		wantPatches []string
+	}{
+		"default": {
+			wantCalls:   []string{string(types.MergePatchType)},
+			wantPatches: []string{`{"metadata":{"labels":{"a":"b"},"resourceVersion":"1","uid":"uid"},"rules":null}`},
+		},
+		"merge patch": {
+			opts:        []StatuslessCommitterOption{WithCommitMode(MergePatchMode)},
+			wantCalls:   []string{string(types.MergePatchType)},
+			wantPatches: []string{`{"metadata":{"labels":{"a":"b"},"resourceVersion":"1","uid":"uid"},"rules":null}`},
+		},
+		"json patch": {
+			opts:      []StatuslessCommitterOption{WithCommitMode(JSONPatchMode)},
+			wantCalls: []string{string(types.JSONPatchType)},
+			wantPatches: []string{`[` +
+				`{"op":"add","path":"/metadata/labels","value":{"a":"b"}},` +
+				`{"op":"add","path":"/metadata/resourceVersion","value":"1"},` +
+				`{"op":"add","path":"/metadata/uid","value":"uid"},` +
+				`{"op":"remove","path":"/rules"}]`},
+		},
+		"strategic merge patch": {
+			opts:        []StatuslessCommitterOption{WithCommitMode(StrategicMergePatchMode)},
+			wantCalls:   []string{string(types.StrategicMergePatchType)},
+			wantPatches: []string{`{"metadata":{"labels":{"a":"b"},"resourceVersion":"1","uid":"uid"},"rules":null}`},
+		},
+		"update": {
+			opts:      []StatuslessCommitterOption{WithCommitMode(UpdateMode)},
+			wantCalls: []string{"update"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			old := newTestClusterRole()
+			obj := old.DeepCopy()
+			obj.Labels = map[string]string{"a": "b"}
+			obj.Rules = nil
+
+			client := &fakeUpdatingClusterRoleClient{}
+			commit := NewStatuslessCommitter[*rbacv1.ClusterRole, *fakeUpdatingClusterRoleClient](client, ShallowCopy[rbacv1.ClusterRole], tc.opts...)
+
+			// nothing is committed without changes
+			require.NoError(t, commit(context.Background(), old, old.DeepCopy()))
+			require.Empty(t, client.calls)
+
+			require.NoError(t, commit(context.Background(), old, obj))
+			require.Equal(t, tc.wantCalls, client.calls)
+			require.Equal(t, tc.wantPatches, client.patches)
+			if tc.wantCalls[0] == "update" {
+				require.Len(t, client.updates, 1)
+				require.Equal(t, obj, client.updates[0])
+			}
+		})
+	}
+}
+
+func TestStatuslessCommitterUpdateWithoutUpdater(t *testing.T) {
+	old := newTestNode(0)
+	obj := old.DeepCopy()
+	obj.Labels = map[string]string{"a": "b"}
+
+	client := &fakeNodeClient{}
+	commit := NewStatuslessCommitterScoped[*corev1.Node](client, ShallowCopy[corev1.Node], WithCommitMode(UpdateMode))
+	require.ErrorContains(t, commit(context.Background(), old, obj), "client does not support updates")
+	require.Empty(t, client.patches)
+}
+
+func TestCreateJSONPatch(t *testing.T) {
+	tests := map[string]struct {
+		old, new string
+		want     string
+	}{
+		"unchanged": {
+			old: `{"a":{"b":1}}`,
+			new: `{"a":{"b":1}}`,
+		},
+		"nested replace and remove": {
+			old:  `{"a":{"b":1,"c":2},"d":[1]}`,
+			new:  `{"a":{"b":3},"d":[1,2]}`,
+			want: `[{"op":"replace","path":"/a/b","value":3},{"op":"remove","path":"/a/c"},{"op":"replace","path":"/d","value":[1,2]}]`,
+		},
+		"escaped keys": {
+			old:  `{"metadata":{"annotations":{}}}`,
+			new:  `{"metadata":{"annotations":{"kcp.io/a~b":"c"}}}`,
+			want: `[{"op":"add","path":"/metadata/annotations/kcp.io~1a~0b","value":"c"}]`,
+		},
+		"object replaces scalar": {
+			old:  `{"a":1}`,
+			new:  `{"a":{"b":null}}`,
+			want: `[{"op":"replace","path":"/a","value":{"b":null}}]`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := createJSONPatch([]byte(tc.old), []byte(tc.new))
+			require.NoError(t, err)
+			require.Equal(t, tc.want, string(got))
+		})
+	}
+}
@@ -53,29 +53,33 @@ type Patcher[R runtime.Object] interface {
 type CommitFunc[Sp any, St any] func(context.Context, *Resource[Sp, St], *Resource[Sp, St]) error
 
 // NewCommitter returns a function that can patch instances of R based on meta,
-// spec or status changes using a cluster-aware patcher.
-func NewCommitter[R runtime.Object, P Patcher[R], Sp any, St any](patcher ClusterPatcher[R, P]) CommitFunc[Sp, St] {
+// spec or status changes using a cluster-aware patcher. Of the options, only
+// WithFieldManager applies.
+func NewCommitter[R runtime.Object, P Patcher[R], Sp any, St any](patcher ClusterPatcher[R, P], opts ...StatuslessCommitterOption) CommitFunc[Sp, St] {
 	r := new(R)
 	focusType := fmt.Sprintf("%T", *r)
+	options := newStatuslessCommitterOptions(opts)
 	return func(ctx context.Context, old, obj *Resource[Sp, St]) error {
 		return withPatchAndSubResources(ctx, focusType, old, obj,
 			func(patchBytes []byte, subresources []string) error {
 				clusterName := logicalcluster.From(old)
-				_, err := patcher.Cluster(clusterName.Path()).Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, subresources...)
+				_, err := patcher.Cluster(clusterName.Path()).Patch(ctx, obj.Name, types.MergePatchType, patchBytes, options.patchOptions(), subresources...)
 				return err
 			})
 	}
 }
 
 // NewCommitterScoped returns a function that can patch instances of R based on
-// meta, spec or status changes using a patcher scoped to a specific cluster.
-func NewCommitterScoped[R runtime.Object, P Patcher[R], Sp any, St any](patcher Patcher[R]) CommitFunc[Sp, St] {
+// meta, spec or status changes using a patcher scoped to a specific cluster. Of
+// the options, only WithFieldManager applies.
+func NewCommitterScoped[R runtime.Object, P Patcher[R], Sp any, St any](patcher Patcher[R], opts ...StatuslessCommitterOption) CommitFunc[Sp, St] {
 	r := new(R)
 	focusType := fmt.Sprintf("%T", *r)
+	options := newStatuslessCommitterOptions(opts)
 	return func(ctx context.Context, old, obj *Resource[Sp, St]) error {
 		return withPatchAndSubResources(ctx, focusType, old, obj,
 			func(patchBytes []byte, subresources []string) error {
-				_, err := patcher.Patch(ctx, obj.Name, types.MergePatchType, patchBytes, metav1.PatchOptions{}, subresources...)
+				_, err := patcher.Patch(ctx, obj.Name, types.MergePatchType, patchBytes, options.patchOptions(), subresources...)
 				return err
 			})
 	}
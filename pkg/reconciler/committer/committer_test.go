@@ -0,0 +1,172 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package committer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	apisv1alpha1 "github.com/kcp-dev/kcp/pkg/apis/apis/v1alpha1"
+)
+
+type fakeAPIBindingPatcher struct {
+	patches       []string
+	subresources  [][]string
+	fieldManagers []string
+}
+
+func (f *fakeAPIBindingPatcher) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*apisv1alpha1.APIBinding, error) {
+	f.patches = append(f.patches, string(data))
+	f.subresources = append(f.subresources, subresources)
+	f.fieldManagers = append(f.fieldManagers, opts.FieldManager)
+	return nil, nil
+}
+
+func TestCommitterSubresources(t *testing.T) {
+	type resource = Resource[*apisv1alpha1.APIBindingSpec, *apisv1alpha1.APIBindingStatus]
+
+	old := &resource{
+		ObjectMeta: metav1.ObjectMeta{Name: "binding", UID: "uid", ResourceVersion: "1"},
+		Spec:       &apisv1alpha1.APIBindingSpec{},
+		Status:     &apisv1alpha1.APIBindingStatus{},
+	}
+
+	tests := map[string]struct {
+		mutate           func(r *resource)
+		wantPatch        string
+		wantSubresources []string
+	}{
+		"only status changed": {
+			mutate: func(r *resource) {
+				r.Status = &apisv1alpha1.APIBindingStatus{Phase: apisv1alpha1.APIBindingPhaseBound}
+			},
+			wantPatch:        `{"metadata":{"resourceVersion":"1","uid":"uid"},"status":{"phase":"Bound"}}`,
+			wantSubresources: []string{"status"},
+		},
+		"only metadata changed": {
+			mutate: func(r *resource) {
+				r.Labels = map[string]string{"a": "b"}
+			},
+			wantPatch: `{"metadata":{"labels":{"a":"b"},"resourceVersion":"1","uid":"uid"}}`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			obj := &resource{
+				ObjectMeta: *old.ObjectMeta.DeepCopy(),
+				Spec:       old.Spec.DeepCopy(),
+				Status:     old.Status.DeepCopy(),
+			}
+			tc.mutate(obj)
+
+			patcher := &fakeAPIBindingPatcher{}
+			commit := NewCommitterScoped[*apisv1alpha1.APIBinding, *fakeAPIBindingPatcher, *apisv1alpha1.APIBindingSpec, *apisv1alpha1.APIBindingStatus](patcher)
+			require.NoError(t, commit(context.Background(), old, obj))
+
+			require.Equal(t, []string{tc.wantPatch}, patcher.patches)
+			require.Equal(t, [][]string{tc.wantSubresources}, patcher.subresources)
+		})
+	}
+}
+
+func TestCommitterFieldManager(t *testing.T) {
+	type resource = Resource[*apisv1alpha1.APIBindingSpec, *apisv1alpha1.APIBindingStatus]
+
+	tests := map[string]struct {
+		commit func(opts ...StatuslessCommitterOption) ([]string, error)
+	}{
+		"committer": {
+			commit: func(opts ...StatuslessCommitterOption) ([]string, error) {
+				old := &resource{ObjectMeta: metav1.ObjectMeta{Name: "binding"}, Spec: &apisv1alpha1.APIBindingSpec{}, Status: &apisv1alpha1.APIBindingStatus{}}
+				obj := &resource{ObjectMeta: metav1.ObjectMeta{Name: "binding"}, Spec: &apisv1alpha1.APIBindingSpec{}, Status: &apisv1alpha1.APIBindingStatus{Phase: apisv1alpha1.APIBindingPhaseBound}}
+				patcher := &fakeAPIBindingPatcher{}
+				commit := NewCommitterScoped[*apisv1alpha1.APIBinding, *fakeAPIBindingPatcher, *apisv1alpha1.APIBindingSpec, *apisv1alpha1.APIBindingStatus](patcher, opts...)
+				err := commit(context.Background(), old, obj)
+				return patcher.fieldManagers, err
+			},
+		},
+		"statusless committer": {
+			commit: func(opts ...StatuslessCommitterOption) ([]string, error) {
+				client := &fakeClusterRoleClient{}
+				commit := NewStatuslessCommitter[*rbacv1.ClusterRole, *fakeClusterRoleClient](client, ShallowCopy[rbacv1.ClusterRole], opts...)
+				err := commit(context.Background(), newTestClusterRole(), newChangedTestClusterRole())
+				return client.fieldManagers, err
+			},
+		},
+		"statusless committer in update mode": {
+			commit: func(opts ...StatuslessCommitterOption) ([]string, error) {
+				client := &fakeUpdatingClusterRoleClient{}
+				commit := NewStatuslessCommitter[*rbacv1.ClusterRole, *fakeUpdatingClusterRoleClient](client, ShallowCopy[rbacv1.ClusterRole], append(opts, WithCommitMode(UpdateMode))...)
+				err := commit(context.Background(), newTestClusterRole(), newChangedTestClusterRole())
+				return client.fieldManagers, err
+			},
+		},
+		"subresource committer": {
+			commit: func(opts ...StatuslessCommitterOption) ([]string, error) {
+				client := &fakeClusterRoleClient{}
+				commit := NewSubresourceCommitterScoped[*rbacv1.ClusterRole](client, "scale", func(old, obj *rbacv1.ClusterRole) ([]byte, error) {
+					return []byte(`{"spec":{"replicas":1}}`), nil
+				}, opts...)
+				err := commit(context.Background(), newTestClusterRole(), newChangedTestClusterRole())
+				return client.fieldManagers, err
+			},
+		},
+		"retrying committer": {
+			commit: func(opts ...StatuslessCommitterOption) ([]string, error) {
+				var retryingOpts []RetryingCommitterOption
+				for _, opt := range opts {
+					retryingOpts = append(retryingOpts, opt.(RetryingCommitterOption))
+				}
+				client := &fakeClusterRoleClient{latest: newTestClusterRole(), conflicts: 1}
+				commit := NewRetryingCommitter[*rbacv1.ClusterRole, *fakeClusterRoleClient](client, ShallowCopy[rbacv1.ClusterRole], retryingOpts...)
+				err := commit(context.Background(), newTestClusterRole(), newChangedTestClusterRole())
+				return client.fieldManagers, err
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			fieldManagers, err := tc.commit()
+			require.NoError(t, err)
+			require.NotEmpty(t, fieldManagers)
+			for _, fieldManager := range fieldManagers {
+				require.Empty(t, fieldManager, "no field manager by default")
+			}
+
+			fieldManagers, err = tc.commit(WithFieldManager("kcp-test-controller"))
+			require.NoError(t, err)
+			require.NotEmpty(t, fieldManagers)
+			for _, fieldManager := range fieldManagers {
+				require.Equal(t, "kcp-test-controller", fieldManager)
+			}
+		})
+	}
+}
+
+func newChangedTestClusterRole() *rbacv1.ClusterRole {
+	r := newTestClusterRole()
+	r.Labels = map[string]string{"a": "b"}
+	return r
+}
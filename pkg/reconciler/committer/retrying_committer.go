@@ -0,0 +1,167 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package committer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+// GetPatcher is the Get and Patch API with a generic to keep use sites type safe.
+type GetPatcher[R runtime.Object] interface {
+	Patcher[R]
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (R, error)
+}
+
+// ClusterGetPatcher is the cluster-aware Get and Patch API with a generic to keep use sites type safe.
+type ClusterGetPatcher[R runtime.Object, P GetPatcher[R]] interface {
+	Cluster(cluster logicalcluster.Path) P
+}
+
+// RetryingCommitterOption configures the committer returned by NewRetryingCommitter.
+type RetryingCommitterOption interface {
+	applyToRetryingCommitter(*retryingCommitterOptions)
+}
+
+type retryingCommitterOptions struct {
+	fieldManager string
+}
+
+func (o retryingCommitterOptions) patchOptions() metav1.PatchOptions {
+	return metav1.PatchOptions{FieldManager: o.fieldManager}
+}
+
+// NewRetryingCommitter returns a function that can patch instances of status-less R like
+// NewStatuslessCommitter does. On a conflict, the object is re-read and the changes from old
+// to new are re-applied on top of it, up to the number of steps of retry.DefaultRetry.
+func NewRetryingCommitter[R StatuslessResource, P GetPatcher[R]](patcher ClusterGetPatcher[R, P], shallowCopy ObjectMetaShallowCopy[R], opts ...RetryingCommitterOption) func(context.Context, R, R) error {
+	focusType := fmt.Sprintf("%T", new(R))
+	var options retryingCommitterOptions
+	for _, opt := range opts {
+		opt.applyToRetryingCommitter(&options)
+	}
+	return func(ctx context.Context, old, obj R) error {
+		logger := klog.FromContext(ctx)
+		clusterName := logicalcluster.From(old)
+
+		if unchanged(shallowCopy, old, obj) {
+			return nil
+		}
+
+		// the changes to re-apply on every attempt, without preconditions
+		changes, err := createMergePatchWithoutPreconditions(shallowCopy, old, obj)
+		if err != nil {
+			return fmt.Errorf("failed to create patch for %s %s: %w", focusType, obj.GetName(), err)
+		}
+
+		current := old
+		attempt := 0
+		err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			if attempt > 0 {
+				latest, err := patcher.Cluster(clusterName.Path()).Get(ctx, old.GetName(), metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+				current = latest
+			}
+			attempt++
+
+			patchBytes, err := rebaseMergePatch(shallowCopy, current, changes)
+			if err != nil {
+				return fmt.Errorf("failed to create patch for %s %s: %w", focusType, obj.GetName(), err)
+			}
+			if patchBytes == nil {
+				return nil
+			}
+
+			logger.V(2).Info(fmt.Sprintf("patching %s", focusType), "patch", string(patchBytes), "attempt", attempt)
+			_, err = patcher.Cluster(clusterName.Path()).Patch(ctx, obj.GetName(), types.MergePatchType, patchBytes, options.patchOptions())
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("failed to patch %s %s|%s: %w", focusType, clusterName, old.GetName(), err)
+		}
+
+		return nil
+	}
+}
+
+// createMergePatchWithoutPreconditions returns the merge patch from old to obj, ignoring the uid and resourceVersion.
+func createMergePatchWithoutPreconditions[R StatuslessResource](shallowCopy ObjectMetaShallowCopy[R], old, obj R) ([]byte, error) {
+	oldData, err := marshalWithoutPreconditions(shallowCopy, old)
+	if err != nil {
+		return nil, err
+	}
+	newData, err := marshalWithoutPreconditions(shallowCopy, obj)
+	if err != nil {
+		return nil, err
+	}
+	return jsonpatch.CreateMergePatch(oldData, newData)
+}
+
+// rebaseMergePatch applies the changes to current and returns the merge patch from current to the result,
+// with the uid and resourceVersion of current as preconditions. It returns nil if current has all changes already.
+func rebaseMergePatch[R StatuslessResource](shallowCopy ObjectMetaShallowCopy[R], current R, changes []byte) ([]byte, error) {
+	currentData, err := marshalWithoutPreconditions(shallowCopy, current)
+	if err != nil {
+		return nil, err
+	}
+	desiredData, err := jsonpatch.MergePatch(currentData, changes)
+	if err != nil {
+		return nil, err
+	}
+	patchBytes, err := jsonpatch.CreateMergePatch(currentData, desiredData)
+	if err != nil {
+		return nil, err
+	}
+	if string(patchBytes) == "{}" {
+		return nil, nil
+	}
+
+	preconditions, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"uid":             current.GetUID(),
+			"resourceVersion": current.GetResourceVersion(),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jsonpatch.MergeMergePatches(patchBytes, preconditions)
+}
+
+func marshalWithoutPreconditions[R StatuslessResource](shallowCopy ObjectMetaShallowCopy[R], obj R) ([]byte, error) {
+	forPatch := shallowCopy(obj)
+	forPatch.SetUID("")
+	forPatch.SetResourceVersion("")
+
+	data, err := json.Marshal(forPatch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to Marshal data for %s|%s: %w", logicalcluster.From(obj), obj.GetName(), err)
+	}
+	return data, nil
+}
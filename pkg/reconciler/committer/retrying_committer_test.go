@@ -0,0 +1,147 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package committer
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type fakeClusterRoleClient struct {
+	latest    *rbacv1.ClusterRole
+	conflicts int
+
+	gets          int
+	patches       [][]byte
+	subresources  [][]string
+	fieldManagers []string
+}
+
+func (f *fakeClusterRoleClient) Cluster(cluster logicalcluster.Path) *fakeClusterRoleClient {
+	return f
+}
+
+func (f *fakeClusterRoleClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*rbacv1.ClusterRole, error) {
+	f.gets++
+	return f.latest.DeepCopy(), nil
+}
+
+func (f *fakeClusterRoleClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*rbacv1.ClusterRole, error) {
+	f.patches = append(f.patches, data)
+	f.subresources = append(f.subresources, subresources)
+	f.fieldManagers = append(f.fieldManagers, opts.FieldManager)
+	if len(f.patches) <= f.conflicts {
+		return nil, apierrors.NewConflict(rbacv1.Resource("clusterroles"), name, nil)
+	}
+	return f.latest, nil
+}
+
+func TestRetryingCommitter(t *testing.T) {
+	old := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "role",
+			UID:             "uid",
+			ResourceVersion: "1",
+			Annotations:     map[string]string{logicalcluster.AnnotationKey: "root:org"},
+		},
+	}
+	obj := old.DeepCopy()
+	obj.Annotations["internal.kcp.io/replicate"] = "apis.kcp.io"
+
+	latest := old.DeepCopy()
+	latest.ResourceVersion = "2"
+	latest.Labels = map[string]string{"other": "actor"}
+
+	tests := map[string]struct {
+		conflicts   int
+		wantPatches int
+		wantGets    int
+		wantErr     bool
+	}{
+		"no conflict": {
+			wantPatches: 1,
+		},
+		"conflict on first write": {
+			conflicts:   1,
+			wantPatches: 2,
+			wantGets:    1,
+		},
+		"conflicts exhaust the retries": {
+			conflicts:   10,
+			wantPatches: 5,
+			wantGets:    4,
+			wantErr:     true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := &fakeClusterRoleClient{latest: latest, conflicts: tc.conflicts}
+			commit := NewRetryingCommitter[*rbacv1.ClusterRole, *fakeClusterRoleClient](client, ShallowCopy[rbacv1.ClusterRole])
+
+			err := commit(context.Background(), old, obj)
+			if tc.wantErr {
+				require.Error(t, err)
+				require.True(t, apierrors.IsConflict(err), "expected conflict error, got %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Len(t, client.patches, tc.wantPatches)
+			require.Equal(t, tc.wantGets, client.gets)
+
+			var first map[string]interface{}
+			require.NoError(t, json.Unmarshal(client.patches[0], &first))
+			require.Equal(t, map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"uid":             "uid",
+					"resourceVersion": "1",
+					"annotations":     map[string]interface{}{"internal.kcp.io/replicate": "apis.kcp.io"},
+				},
+			}, first)
+
+			if tc.conflicts == 1 {
+				var retried map[string]interface{}
+				require.NoError(t, json.Unmarshal(client.patches[1], &retried))
+				require.Equal(t, map[string]interface{}{
+					"metadata": map[string]interface{}{
+						"uid":             "uid",
+						"resourceVersion": "2",
+						"annotations":     map[string]interface{}{"internal.kcp.io/replicate": "apis.kcp.io"},
+					},
+				}, retried, "retried patch must be based on the re-read object and keep its labels")
+			}
+		})
+	}
+}
+
+func TestRetryingCommitterUnchanged(t *testing.T) {
+	old := &rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{Name: "role", ResourceVersion: "1"}}
+	client := &fakeClusterRoleClient{latest: old}
+	commit := NewRetryingCommitter[*rbacv1.ClusterRole, *fakeClusterRoleClient](client, ShallowCopy[rbacv1.ClusterRole])
+
+	require.NoError(t, commit(context.Background(), old, old.DeepCopy()))
+	require.Empty(t, client.patches)
+}
@@ -20,14 +20,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
 
 	jsonpatch "github.com/evanphx/json-patch"
+	kcpcache "github.com/kcp-dev/apimachinery/v2/pkg/cache"
 	"github.com/kcp-dev/logicalcluster/v3"
 
 	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 )
 
@@ -47,63 +51,237 @@ func ShallowCopy[R any](obj *R) *R {
 	return ret
 }
 
-// NewStatuslessCommitter returns a function that can patch instances of status-less R.
-func NewStatuslessCommitter[R StatuslessResource, P Patcher[R]](patcher ClusterPatcher[R, P], shallowCopy ObjectMetaShallowCopy[R]) func(context.Context, R, R) error {
-	focusType := fmt.Sprintf("%T", new(R))
-	return func(ctx context.Context, old, obj R) error {
-		logger := klog.FromContext(ctx)
-		clusterName := logicalcluster.From(old)
-
-		patchBytes, err := generateStatusLessPatchAndSubResources(shallowCopy, old, obj)
-		if err != nil {
-			return fmt.Errorf("failed to create patch for %s %s: %w", focusType, obj.GetName(), err)
-		}
+// statusFieldIndexes caches the index of the Status field by struct type, or nil if there is none.
+var statusFieldIndexes sync.Map
 
-		if len(patchBytes) == 0 {
-			return nil
-		}
+// SpecOnlyCopy does a shallow copy like ShallowCopy, but leaves the Status field, if any, empty.
+// Committers marshal both copies to compute a patch, so for objects with a large status this
+// saves allocations and CPU in hot reconcile loops. Only use it for committers that never patch
+// status, e.g. NewStatuslessCommitter on resources with a status subresource; status changes are
+// invisible to them.
+func SpecOnlyCopy[R any](obj *R) *R {
+	ret := ShallowCopy(obj)
+	v := reflect.ValueOf(ret).Elem()
+	if index := statusFieldIndex(v.Type()); index != nil {
+		status := v.FieldByIndex(index)
+		status.Set(reflect.Zero(status.Type()))
+	}
+	return ret
+}
 
-		logger.V(2).Info(fmt.Sprintf("patching %s", focusType), "patch", string(patchBytes))
-		_, err = patcher.Cluster(clusterName.Path()).Patch(ctx, obj.GetName(), types.MergePatchType, patchBytes, metav1.PatchOptions{})
-		if err != nil {
-			return fmt.Errorf("failed to patch %s %s|%s: %w", focusType, clusterName, old.GetName(), err)
+func statusFieldIndex(t reflect.Type) []int {
+	if index, ok := statusFieldIndexes.Load(t); ok {
+		return index.([]int)
+	}
+	var index []int
+	if t.Kind() == reflect.Struct {
+		if f, ok := t.FieldByName("Status"); ok {
+			index = f.Index
 		}
-
-		return nil
 	}
+	statusFieldIndexes.Store(t, index)
+	return index
+}
+
+// NewStatuslessCommitter returns a function that can patch instances of status-less R.
+func NewStatuslessCommitter[R StatuslessResource, P Patcher[R]](patcher ClusterPatcher[R, P], shallowCopy ObjectMetaShallowCopy[R], opts ...StatuslessCommitterOption) func(context.Context, R, R) error {
+	return newStatuslessCommitter(func(clusterName logicalcluster.Name) Patcher[R] {
+		return patcher.Cluster(clusterName.Path())
+	}, shallowCopy, opts)
 }
 
 // NewStatuslessCommitterScoped returns a function that can patch instances of status-less R changes using a scoped patcher.
-func NewStatuslessCommitterScoped[R StatuslessResource](patcher Patcher[R], shallowCopy ObjectMetaShallowCopy[R]) func(context.Context, R, R) error {
-	focusType := fmt.Sprintf("%T", new(R))
-	return func(ctx context.Context, old, obj R) error {
-		logger := klog.FromContext(ctx)
-		clusterName := logicalcluster.From(old)
-
-		patchBytes, err := generateStatusLessPatchAndSubResources(shallowCopy, old, obj)
-		if err != nil {
-			return fmt.Errorf("failed to create patch for %s %s: %w", focusType, obj.GetName(), err)
+func NewStatuslessCommitterScoped[R StatuslessResource](patcher Patcher[R], shallowCopy ObjectMetaShallowCopy[R], opts ...StatuslessCommitterOption) func(context.Context, R, R) error {
+	return newStatuslessCommitter(func(logicalcluster.Name) Patcher[R] {
+		return patcher
+	}, shallowCopy, opts)
+}
+
+func newStatuslessCommitter[R StatuslessResource](patcherFor func(logicalcluster.Name) Patcher[R], shallowCopy ObjectMetaShallowCopy[R], opts []StatuslessCommitterOption) func(context.Context, R, R) error {
+	options := newStatuslessCommitterOptions(opts)
+	if options.mode == UpdateMode {
+		return newUpdateCommitter(patcherFor, shallowCopy, options)
+	}
+	return newSubresourceCommitter(patcherFor, "", statuslessPatchFunc(shallowCopy, options.mode), opts)
+}
+
+func statuslessPatchFunc[R StatuslessResource](shallowCopy ObjectMetaShallowCopy[R], mode CommitMode) PatchFunc[R] {
+	return func(old, obj R) ([]byte, error) {
+		switch mode {
+		case JSONPatchMode:
+			return generateStatuslessJSONPatch(shallowCopy, old, obj)
+		case StrategicMergePatchMode:
+			return generateStatuslessStrategicMergePatch(shallowCopy, old, obj)
+		default:
+			return generateStatusLessPatchAndSubResources(shallowCopy, old, obj)
 		}
+	}
+}
+
+// unchanged returns true if old and obj are equal, ignoring the resourceVersion and managedFields
+// which are maintained by the server and cannot be changed through a patch anyway.
+func unchanged[R StatuslessResource](shallowCopy ObjectMetaShallowCopy[R], old, obj R) bool {
+	if equality.Semantic.DeepEqual(old, obj) {
+		return true
+	}
+
+	oldForCompare := shallowCopy(old)
+	oldForCompare.SetResourceVersion("")
+	oldForCompare.SetManagedFields(nil)
+
+	newForCompare := shallowCopy(obj)
+	newForCompare.SetResourceVersion("")
+	newForCompare.SetManagedFields(nil)
+
+	return equality.Semantic.DeepEqual(oldForCompare, newForCompare)
+}
+
+// StatuslessCommitterOption configures the committers returned by NewStatuslessCommitter and NewStatuslessCommitterScoped.
+// Other committers document which options they honour.
+type StatuslessCommitterOption interface {
+	applyToStatuslessCommitter(*statuslessCommitterOptions)
+}
+
+type statuslessCommitterOptions struct {
+	logDiff      bool
+	mode         CommitMode
+	fieldManager string
+}
+
+func newStatuslessCommitterOptions(opts []StatuslessCommitterOption) statuslessCommitterOptions {
+	var options statuslessCommitterOptions
+	for _, opt := range opts {
+		opt.applyToStatuslessCommitter(&options)
+	}
+	return options
+}
+
+type diffLoggingOption struct{}
+
+func (diffLoggingOption) applyToStatuslessCommitter(o *statuslessCommitterOptions) {
+	o.logDiff = true
+}
+
+// WithDiffLogging makes the committer log the fields changed by every patch it sends, at verbosity 4.
+func WithDiffLogging() StatuslessCommitterOption {
+	return diffLoggingOption{}
+}
+
+// FieldManagerOption sets the field manager of a committer. All committers accept it.
+type FieldManagerOption string
 
-		if len(patchBytes) == 0 {
-			return nil
+func (f FieldManagerOption) applyToStatuslessCommitter(o *statuslessCommitterOptions) {
+	o.fieldManager = string(f)
+}
+
+func (f FieldManagerOption) applyToApplyCommitter(o *applyCommitterOptions) {
+	o.fieldManager = string(f)
+}
+
+func (f FieldManagerOption) applyToRetryingCommitter(o *retryingCommitterOptions) {
+	o.fieldManager = string(f)
+}
+
+// WithFieldManager makes the committer send fieldManager as the field manager of every patch
+// or update, such that managedFields attribute the changes to it. Controllers usually pass their
+// name. If empty, the server derives the manager from the user agent.
+func WithFieldManager(fieldManager string) FieldManagerOption {
+	return FieldManagerOption(fieldManager)
+}
+
+func (o statuslessCommitterOptions) patchOptions() metav1.PatchOptions {
+	return metav1.PatchOptions{FieldManager: o.fieldManager}
+}
+
+func logDiff(logger klog.Logger, obj metav1.Object, patchBytes []byte) {
+	logger = logger.V(4)
+	if !logger.Enabled() {
+		return
+	}
+
+	var fields []string
+	if len(patchBytes) > 0 && patchBytes[0] == '[' {
+		var ops []jsonPatchOperation
+		if err := json.Unmarshal(patchBytes, &ops); err != nil {
+			logger.Error(err, "failed to unmarshal patch for diff logging")
+			return
+		}
+		fields = changedJSONPatchFields(ops)
+	} else {
+		var patch map[string]interface{}
+		if err := json.Unmarshal(patchBytes, &patch); err != nil {
+			logger.Error(err, "failed to unmarshal patch for diff logging")
+			return
 		}
+		// the preconditions are not changes
+		if metadata, ok := patch["metadata"].(map[string]interface{}); ok {
+			delete(metadata, "uid")
+			delete(metadata, "resourceVersion")
+		}
+		fields = changedFields("", patch)
+	}
 
-		logger.V(2).Info(fmt.Sprintf("patching %s", focusType), "patch", string(patchBytes))
-		_, err = patcher.Patch(ctx, obj.GetName(), types.MergePatchType, patchBytes, metav1.PatchOptions{})
-		if err != nil {
-			return fmt.Errorf("failed to patch %s %s|%s: %w", focusType, clusterName, old.GetName(), err)
+	key := kcpcache.ToClusterAwareKey(logicalcluster.From(obj).String(), obj.GetNamespace(), obj.GetName())
+	logger.Info("committed diff", "key", key, "fields_changed", fields, "patch", string(patchBytes))
+}
+
+// changedJSONPatchFields returns the sorted paths changed by a JSON patch, in the notation of changedFields.
+func changedJSONPatchFields(ops []jsonPatchOperation) []string {
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+	var fields []string
+	for _, op := range ops {
+		if op.Path == "/metadata/uid" || op.Path == "/metadata/resourceVersion" {
+			continue
 		}
+		segments := strings.Split(strings.TrimPrefix(op.Path, "/"), "/")
+		for i := range segments {
+			segments[i] = unescape.Replace(segments[i])
+		}
+		fields = append(fields, strings.Join(segments, "."))
+	}
+	sort.Strings(fields)
+	return fields
+}
 
-		return nil
+// changedFields returns the sorted paths of the leaves in a merge patch.
+func changedFields(prefix string, patch map[string]interface{}) []string {
+	var fields []string
+	for k, v := range patch {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			fields = append(fields, changedFields(path, nested)...)
+			continue
+		}
+		fields = append(fields, path)
 	}
+	sort.Strings(fields)
+	return fields
 }
 
 func generateStatusLessPatchAndSubResources[R StatuslessResource](shallowCopy ObjectMetaShallowCopy[R], old, obj R) ([]byte, error) {
-	if equality.Semantic.DeepEqual(old, obj) {
+	if unchanged(shallowCopy, old, obj) {
 		return nil, nil
 	}
 
+	oldData, newData, err := marshalForPatch(shallowCopy, old, obj)
+	if err != nil {
+		return nil, err
+	}
+
+	patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create patch for %s|%s: %w", logicalcluster.From(old), old.GetName(), err)
+	}
+
+	return patchBytes, nil
+}
+
+// marshalForPatch marshals old without and obj with the uid and resourceVersion of old, such that
+// they appear in a patch computed from the two as preconditions.
+func marshalForPatch[R StatuslessResource](shallowCopy ObjectMetaShallowCopy[R], old, obj R) ([]byte, []byte, error) {
 	clusterName := logicalcluster.From(old)
 	name := old.GetName()
 
@@ -114,7 +292,7 @@ func generateStatusLessPatchAndSubResources[R StatuslessResource](shallowCopy Ob
 
 	oldData, err := json.Marshal(oldForPatch)
 	if err != nil {
-		return nil, fmt.Errorf("failed to Marshal old data for %s|%s: %w", clusterName, name, err)
+		return nil, nil, fmt.Errorf("failed to Marshal old data for %s|%s: %w", clusterName, name, err)
 	}
 
 	newForPatch := shallowCopy(obj)
@@ -124,13 +302,8 @@ func generateStatusLessPatchAndSubResources[R StatuslessResource](shallowCopy Ob
 
 	newData, err := json.Marshal(newForPatch)
 	if err != nil {
-		return nil, fmt.Errorf("failed to Marshal new data for %s|%s: %w", clusterName, name, err)
+		return nil, nil, fmt.Errorf("failed to Marshal new data for %s|%s: %w", clusterName, name, err)
 	}
 
-	patchBytes, err := jsonpatch.CreateMergePatch(oldData, newData)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create patch for %s|%s: %w", clusterName, name, err)
-	}
-
-	return patchBytes, nil
+	return oldData, newData, nil
 }
@@ -0,0 +1,238 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package committer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/kcp-dev/logicalcluster/v3"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+func newTestClusterRole() *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "role",
+			UID:             "uid",
+			ResourceVersion: "1",
+			Annotations:     map[string]string{logicalcluster.AnnotationKey: "root:org"},
+			ManagedFields:   []metav1.ManagedFieldsEntry{{Manager: "kcp"}},
+		},
+		Rules: []rbacv1.PolicyRule{
+			{APIGroups: []string{"apis.kcp.io"}, Resources: []string{"apiexports"}, Verbs: []string{"bind"}},
+		},
+	}
+}
+
+func TestStatuslessCommitterUnchanged(t *testing.T) {
+	tests := map[string]struct {
+		mutate      func(r *rbacv1.ClusterRole)
+		wantPatches int
+	}{
+		"identical": {
+			mutate: func(r *rbacv1.ClusterRole) {},
+		},
+		"different resourceVersion": {
+			mutate: func(r *rbacv1.ClusterRole) {
+				r.ResourceVersion = "2"
+			},
+		},
+		"different managedFields": {
+			mutate: func(r *rbacv1.ClusterRole) {
+				r.ManagedFields = nil
+			},
+		},
+		"different annotations": {
+			mutate: func(r *rbacv1.ClusterRole) {
+				r.Annotations["internal.kcp.io/replicate"] = "apis.kcp.io"
+			},
+			wantPatches: 1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			old := newTestClusterRole()
+			obj := old.DeepCopy()
+			tc.mutate(obj)
+
+			client := &fakeClusterRoleClient{latest: old}
+			commit := NewStatuslessCommitter[*rbacv1.ClusterRole, *fakeClusterRoleClient](client, ShallowCopy[rbacv1.ClusterRole])
+			require.NoError(t, commit(context.Background(), old, obj))
+			require.Len(t, client.patches, tc.wantPatches)
+			require.Zero(t, client.gets)
+		})
+	}
+}
+
+func BenchmarkStatuslessCommitterUnchanged(b *testing.B) {
+	old := newTestClusterRole()
+	obj := old.DeepCopy()
+	client := &fakeClusterRoleClient{latest: old}
+	commit := NewStatuslessCommitter[*rbacv1.ClusterRole, *fakeClusterRoleClient](client, ShallowCopy[rbacv1.ClusterRole])
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := commit(ctx, old, obj); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestStatuslessCommitterDiffLogging(t *testing.T) {
+	tests := map[string]struct {
+		mutate  func(r *rbacv1.ClusterRole)
+		opts    []StatuslessCommitterOption
+		wantLog string
+	}{
+		"no patch": {
+			mutate: func(r *rbacv1.ClusterRole) {},
+			opts:   []StatuslessCommitterOption{WithDiffLogging()},
+		},
+		"patch without diff logging": {
+			mutate: func(r *rbacv1.ClusterRole) {
+				r.Annotations["internal.kcp.io/replicate"] = "apis.kcp.io"
+			},
+		},
+		"patch with diff logging": {
+			mutate: func(r *rbacv1.ClusterRole) {
+				r.Annotations["internal.kcp.io/replicate"] = "apis.kcp.io"
+				r.Labels = map[string]string{"a": "b"}
+			},
+			opts:    []StatuslessCommitterOption{WithDiffLogging()},
+			wantLog: `"key"="root:org|role" "fields_changed"=["metadata.annotations.internal.kcp.io/replicate","metadata.labels.a"]`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			old := newTestClusterRole()
+			obj := old.DeepCopy()
+			tc.mutate(obj)
+
+			var diffLogs []string
+			logger := funcr.New(func(prefix, args string) {
+				if strings.Contains(args, `"msg"="committed diff"`) {
+					diffLogs = append(diffLogs, args)
+				}
+			}, funcr.Options{Verbosity: 4})
+			ctx := klog.NewContext(context.Background(), logger)
+
+			client := &fakeClusterRoleClient{latest: old}
+			commit := NewStatuslessCommitter[*rbacv1.ClusterRole, *fakeClusterRoleClient](client, ShallowCopy[rbacv1.ClusterRole], tc.opts...)
+			require.NoError(t, commit(ctx, old, obj))
+
+			if tc.wantLog == "" {
+				require.Empty(t, diffLogs)
+				return
+			}
+			require.Len(t, diffLogs, 1)
+			require.Contains(t, diffLogs[0], tc.wantLog)
+		})
+	}
+}
+
+func newTestNode(images int) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "node",
+			UID:             "uid",
+			ResourceVersion: "1",
+			Annotations:     map[string]string{logicalcluster.AnnotationKey: "root:org"},
+		},
+		Spec: corev1.NodeSpec{PodCIDR: "10.0.0.0/24"},
+	}
+	for i := 0; i < images; i++ {
+		node.Status.Images = append(node.Status.Images, corev1.ContainerImage{
+			Names:     []string{fmt.Sprintf("registry.example.com/image-%d@sha256:0123456789abcdef", i), fmt.Sprintf("registry.example.com/image-%d:v1", i)},
+			SizeBytes: int64(i),
+		})
+	}
+	return node
+}
+
+func TestSpecOnlyCopy(t *testing.T) {
+	node := newTestNode(2)
+	copied := SpecOnlyCopy(node)
+	require.Empty(t, copied.Status)
+	require.Equal(t, node.Spec, copied.Spec)
+	require.Equal(t, node.ObjectMeta, copied.ObjectMeta)
+	require.Len(t, node.Status.Images, 2, "input must not be mutated")
+
+	copied.SetResourceVersion("")
+	require.Equal(t, "1", node.ResourceVersion, "input must not be mutated")
+
+	// types without status are copied as a whole
+	role := newTestClusterRole()
+	require.Equal(t, role, SpecOnlyCopy(role))
+}
+
+func TestSpecOnlyCopyIgnoresStatus(t *testing.T) {
+	old := newTestNode(2)
+	obj := old.DeepCopy()
+	obj.Status.Images = nil
+
+	client := &fakeNodeClient{}
+	commit := NewStatuslessCommitterScoped[*corev1.Node](client, SpecOnlyCopy[corev1.Node])
+	require.NoError(t, commit(context.Background(), old, obj))
+	require.Empty(t, client.patches)
+
+	obj.Labels = map[string]string{"a": "b"}
+	require.NoError(t, commit(context.Background(), old, obj))
+	require.Equal(t, []string{`{"metadata":{"labels":{"a":"b"},"resourceVersion":"1","uid":"uid"}}`}, client.patches)
+}
+
+func BenchmarkStatuslessPatch(b *testing.B) {
+	old := newTestNode(500)
+	obj := old.DeepCopy()
+	obj.Labels = map[string]string{"a": "b"}
+
+	for name, shallowCopy := range map[string]ObjectMetaShallowCopy[*corev1.Node]{
+		"ShallowCopy":  ShallowCopy[corev1.Node],
+		"SpecOnlyCopy": SpecOnlyCopy[corev1.Node],
+	} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := generateStatusLessPatchAndSubResources(shallowCopy, old, obj); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+type fakeNodeClient struct {
+	patches []string
+}
+
+func (f *fakeNodeClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (*corev1.Node, error) {
+	f.patches = append(f.patches, string(data))
+	return nil, nil
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package committer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kcp-dev/logicalcluster/v3"
+
+	"k8s.io/klog/v2"
+)
+
+// PatchFunc computes the patch from old to obj, or nil if there is nothing to patch. It is a merge
+// patch unless the committer is configured with another mode through WithCommitMode.
+type PatchFunc[R any] func(old, obj R) ([]byte, error)
+
+// NewSubresourceCommitter returns a function that merge patches the given subresource of instances
+// of R, e.g. "status" or "scale", with the patches computed by generatePatch. The main resource is
+// patched if subresource is empty.
+func NewSubresourceCommitter[R StatuslessResource, P Patcher[R]](patcher ClusterPatcher[R, P], subresource string, generatePatch PatchFunc[R], opts ...StatuslessCommitterOption) func(context.Context, R, R) error {
+	return newSubresourceCommitter(func(clusterName logicalcluster.Name) Patcher[R] {
+		return patcher.Cluster(clusterName.Path())
+	}, subresource, generatePatch, opts)
+}
+
+// NewSubresourceCommitterScoped is NewSubresourceCommitter using a scoped patcher.
+func NewSubresourceCommitterScoped[R StatuslessResource](patcher Patcher[R], subresource string, generatePatch PatchFunc[R], opts ...StatuslessCommitterOption) func(context.Context, R, R) error {
+	return newSubresourceCommitter(func(logicalcluster.Name) Patcher[R] {
+		return patcher
+	}, subresource, generatePatch, opts)
+}
+
+func newSubresourceCommitter[R StatuslessResource](patcherFor func(logicalcluster.Name) Patcher[R], subresource string, generatePatch PatchFunc[R], opts []StatuslessCommitterOption) func(context.Context, R, R) error {
+	focusType := fmt.Sprintf("%T", new(R))
+	var subresources []string
+	if subresource != "" {
+		focusType = fmt.Sprintf("%s %s", focusType, subresource)
+		subresources = []string{subresource}
+	}
+	options := newStatuslessCommitterOptions(opts)
+	patchType := options.mode.patchType()
+	return func(ctx context.Context, old, obj R) error {
+		logger := klog.FromContext(ctx)
+		clusterName := logicalcluster.From(old)
+
+		patchBytes, err := generatePatch(old, obj)
+		if err != nil {
+			return fmt.Errorf("failed to create patch for %s %s: %w", focusType, obj.GetName(), err)
+		}
+
+		if len(patchBytes) == 0 {
+			return nil
+		}
+
+		logger.V(2).Info(fmt.Sprintf("patching %s", focusType), "patch", string(patchBytes))
+		_, err = patcherFor(clusterName).Patch(ctx, obj.GetName(), patchType, patchBytes, options.patchOptions(), subresources...)
+		if err != nil {
+			return fmt.Errorf("failed to patch %s %s|%s: %w", focusType, clusterName, old.GetName(), err)
+		}
+
+		if options.logDiff {
+			logDiff(logger, obj, patchBytes)
+		}
+
+		return nil
+	}
+}
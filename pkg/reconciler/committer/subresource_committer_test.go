@@ -0,0 +1,108 @@
+/*
+Copyright 2023 The KCP Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package committer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+func TestSubresourceCommitter(t *testing.T) {
+	scalePatch := func(old, obj *rbacv1.ClusterRole) ([]byte, error) {
+		if old.Labels["replicas"] == obj.Labels["replicas"] {
+			return nil, nil
+		}
+		return []byte(`{"spec":{"replicas":` + obj.Labels["replicas"] + `}}`), nil
+	}
+
+	tests := map[string]struct {
+		subresource      string
+		generatePatch    PatchFunc[*rbacv1.ClusterRole]
+		mutate           func(r *rbacv1.ClusterRole)
+		wantPatches      []string
+		wantSubresources [][]string
+		wantErr          bool
+	}{
+		"custom subresource": {
+			subresource:   "scale",
+			generatePatch: scalePatch,
+			mutate: func(r *rbacv1.ClusterRole) {
+				r.Labels = map[string]string{"replicas": "3"}
+			},
+			wantPatches:      []string{`{"spec":{"replicas":3}}`},
+			wantSubresources: [][]string{{"scale"}},
+		},
+		"custom subresource without changes": {
+			subresource:   "scale",
+			generatePatch: scalePatch,
+			mutate:        func(r *rbacv1.ClusterRole) {},
+		},
+		"main resource": {
+			generatePatch: scalePatch,
+			mutate: func(r *rbacv1.ClusterRole) {
+				r.Labels = map[string]string{"replicas": "1"}
+			},
+			wantPatches:      []string{`{"spec":{"replicas":1}}`},
+			wantSubresources: [][]string{nil},
+		},
+		"patch function fails": {
+			subresource: "scale",
+			generatePatch: func(old, obj *rbacv1.ClusterRole) ([]byte, error) {
+				return nil, errors.New("boom")
+			},
+			mutate:  func(r *rbacv1.ClusterRole) {},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			old := newTestClusterRole()
+			obj := old.DeepCopy()
+			tc.mutate(obj)
+
+			for scope, newCommitter := range map[string]func(client *fakeClusterRoleClient) func(context.Context, *rbacv1.ClusterRole, *rbacv1.ClusterRole) error{
+				"cluster": func(client *fakeClusterRoleClient) func(context.Context, *rbacv1.ClusterRole, *rbacv1.ClusterRole) error {
+					return NewSubresourceCommitter[*rbacv1.ClusterRole, *fakeClusterRoleClient](client, tc.subresource, tc.generatePatch)
+				},
+				"scoped": func(client *fakeClusterRoleClient) func(context.Context, *rbacv1.ClusterRole, *rbacv1.ClusterRole) error {
+					return NewSubresourceCommitterScoped[*rbacv1.ClusterRole](client, tc.subresource, tc.generatePatch)
+				},
+			} {
+				client := &fakeClusterRoleClient{latest: old}
+				err := newCommitter(client)(context.Background(), old, obj)
+				if tc.wantErr {
+					require.Error(t, err, scope)
+				} else {
+					require.NoError(t, err, scope)
+				}
+
+				var patches []string
+				for _, p := range client.patches {
+					patches = append(patches, string(p))
+				}
+				require.Equal(t, tc.wantPatches, patches, scope)
+				require.Equal(t, tc.wantSubresources, client.subresources, scope)
+			}
+		})
+	}
+}
@@ -42,7 +42,6 @@ import (
 	apisv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/apis/v1alpha1"
 	tenancyv1alpha1listers "github.com/kcp-dev/kcp/pkg/client/listers/tenancy/v1alpha1"
 	"github.com/kcp-dev/kcp/pkg/logging"
-	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
 	"github.com/kcp-dev/kcp/pkg/server/filters"
 )
 
@@ -64,6 +63,9 @@ type apiBindingAwareCRDClusterLister struct {
 	apiExportIndexer cache.Indexer
 
 	getAPIResourceSchema func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error)
+
+	// boundCRDsClusterName is the logical cluster the CRDs of APIBindings live in.
+	boundCRDsClusterName logicalcluster.Name
 }
 
 func (a *apiBindingAwareCRDClusterLister) Cluster(name logicalcluster.Name) kcp.ClusterAwareCRDLister {
@@ -118,10 +120,10 @@ func (c *apiBindingAwareCRDLister) List(ctx context.Context, selector labels.Sel
 			logger := logging.WithObject(logger, &apiextensionsv1.CustomResourceDefinition{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:        boundResource.Schema.UID,
-					Annotations: map[string]string{logicalcluster.AnnotationKey: apibinding.SystemBoundCRDsClusterName.String()},
+					Annotations: map[string]string{logicalcluster.AnnotationKey: c.boundCRDsClusterName.String()},
 				},
 			})
-			crd, err := c.crdLister.Cluster(apibinding.SystemBoundCRDsClusterName).Get(boundResource.Schema.UID)
+			crd, err := c.crdLister.Cluster(c.boundCRDsClusterName).Get(boundResource.Schema.UID)
 			if err != nil {
 				logger.Error(err, "error getting bound CRD")
 				continue
@@ -337,7 +339,7 @@ func (c *apiBindingAwareCRDLister) getForIdentityWildcard(name, identity string)
 		return nil, apierrors.NewNotFound(apiextensionsv1.Resource("customresourcedefinitions"), name)
 	}
 
-	crd, err := c.crdLister.Cluster(apibinding.SystemBoundCRDsClusterName).Get(boundCRDName)
+	crd, err := c.crdLister.Cluster(c.boundCRDsClusterName).Get(boundCRDName)
 	if err != nil {
 		return nil, err
 	}
@@ -385,7 +387,7 @@ func (c *apiBindingAwareCRDLister) get(clusterName logicalcluster.Name, name, id
 			matchingIdentity := identity == "" || boundResource.Schema.IdentityHash == identity
 
 			if boundResource.Group == group && boundResource.Resource == resource && matchingIdentity {
-				crd, err = c.crdLister.Cluster(apibinding.SystemBoundCRDsClusterName).Get(boundResource.Schema.UID)
+				crd, err = c.crdLister.Cluster(c.boundCRDsClusterName).Get(boundResource.Schema.UID)
 				if err != nil && apierrors.IsNotFound(err) {
 					// If we got here, it means there is supposed to be a CRD coming from an APIBinding, but
 					// the CRD doesn't exist for some reason.
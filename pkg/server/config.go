@@ -58,6 +58,7 @@ import (
 	kcpfeatures "github.com/kcp-dev/kcp/pkg/features"
 	"github.com/kcp-dev/kcp/pkg/indexers"
 	"github.com/kcp-dev/kcp/pkg/informer"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
 	"github.com/kcp-dev/kcp/pkg/server/bootstrap"
 	kcpfilters "github.com/kcp-dev/kcp/pkg/server/filters"
 	kcpserveroptions "github.com/kcp-dev/kcp/pkg/server/options"
@@ -108,6 +109,10 @@ type ExtraConfig struct {
 	preHandlerChainMux   *handlerChainMuxes
 	quotaAdmissionStopCh chan struct{}
 
+	// BoundCRDsClusterName is the logical cluster the CRDs of APIBindings are created in. The apibinding
+	// and crdcleanup controllers, the CRD lister and the admission plugins all use it.
+	BoundCRDsClusterName logicalcluster.Name
+
 	// URL getters depending on genericspiserver.ExternalAddress which is initialized on server run
 	ShardBaseURL             func() string
 	ShardExternalURL         func() string
@@ -160,6 +165,7 @@ func NewConfig(opts *kcpserveroptions.CompletedOptions) (*Config, error) {
 	c := &Config{
 		Options: opts,
 	}
+	c.BoundCRDsClusterName = apibinding.SystemBoundCRDsClusterName
 
 	if opts.Extra.ProfilerAddress != "" {
 		//nolint:errcheck,gosec
@@ -399,6 +405,7 @@ func NewConfig(opts *kcpserveroptions.CompletedOptions) (*Config, error) {
 		// with the default secure port, when the config is later completed.
 		kcpadmissioninitializers.NewKubeQuotaConfigurationInitializer(quotaConfiguration),
 		kcpadmissioninitializers.NewServerShutdownInitializer(c.quotaAdmissionStopCh),
+		kcpadmissioninitializers.NewBoundCRDsClusterNameInitializer(c.BoundCRDsClusterName),
 	}
 
 	c.ShardBaseURL = func() string {
@@ -458,6 +465,7 @@ func NewConfig(opts *kcpserveroptions.CompletedOptions) (*Config, error) {
 		getAPIResourceSchema: func(clusterName logicalcluster.Name, name string) (*apisv1alpha1.APIResourceSchema, error) {
 			return c.KcpSharedInformerFactory.Apis().V1alpha1().APIResourceSchemas().Lister().Cluster(clusterName).Get(name)
 		},
+		boundCRDsClusterName: c.BoundCRDsClusterName,
 	}
 	c.ApiExtensions.ExtraConfig.Client = c.ApiExtensionsClusterClient
 	c.ApiExtensions.ExtraConfig.Informers = c.ApiExtensionsSharedInformerFactory
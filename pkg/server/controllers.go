@@ -32,6 +32,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kcpapiextensionsclientset "k8s.io/apiextensions-apiserver/pkg/client/kcp/clientset/versioned"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	genericapiserver "k8s.io/apiserver/pkg/server"
@@ -58,13 +59,17 @@ import (
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiexport"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiexportendpointslice"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiresource"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiresourceschemareferences"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/crdcleanup"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/extraannotationsync"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/identitycache"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/permissionclaimlabel"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/replicationclusterrole"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/replicationclusterrolebinding"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/replicationrole"
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/replicationrolebinding"
 	"github.com/kcp-dev/kcp/pkg/reconciler/cache/replication"
+	"github.com/kcp-dev/kcp/pkg/reconciler/clusterdeletion"
 	logicalclusterctrl "github.com/kcp-dev/kcp/pkg/reconciler/core/logicalcluster"
 	"github.com/kcp-dev/kcp/pkg/reconciler/core/logicalclusterdeletion"
 	"github.com/kcp-dev/kcp/pkg/reconciler/core/shard"
@@ -660,9 +665,18 @@ func (s *Server) installAPIBindingController(ctx context.Context, config *rest.C
 	if err != nil {
 		return err
 	}
+	kubeClusterClient, err := kcpkubernetesclientset.NewForConfig(apiBindingConfig)
+	if err != nil {
+		return err
+	}
+
+	options := s.Options.Controllers.APIBinding
+	options.BoundCRDsClusterName = s.BoundCRDsClusterName
+	options.IsLogicalClusterDeleting = clusterdeletion.NewInformerIsDeletingFunc(s.KcpSharedInformerFactory.Core().V1alpha1().LogicalClusters())
 
 	c, err := apibinding.NewController(
 		crdClusterClient,
+		kubeClusterClient,
 		kcpClusterClient,
 		dynamicClusterClient,
 		ddsif,
@@ -674,6 +688,7 @@ func (s *Server) installAPIBindingController(ctx context.Context, config *rest.C
 		s.CacheKcpSharedInformerFactory.Apis().V1alpha1().APIResourceSchemas(),
 		s.CacheKcpSharedInformerFactory.Apis().V1alpha1().APIConversions(),
 		s.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions(),
+		options,
 	)
 	if err != nil {
 		return err
@@ -871,6 +886,7 @@ func (s *Server) installCRDCleanupController(ctx context.Context, config *rest.C
 		s.ApiExtensionsSharedInformerFactory.Apiextensions().V1().CustomResourceDefinitions(),
 		crdClusterClient,
 		s.KcpSharedInformerFactory.Apis().V1alpha1().APIBindings(),
+		s.BoundCRDsClusterName,
 	)
 	if err != nil {
 		return err
@@ -919,6 +935,9 @@ func (s *Server) installAPIExportController(ctx context.Context, config *rest.Co
 		kubeClusterClient,
 		s.KubeSharedInformerFactory.Rbac().V1().ClusterRoles(),
 		s.KubeSharedInformerFactory.Rbac().V1().ClusterRoleBindings(),
+		s.KcpSharedInformerFactory.Core().V1alpha1().LogicalClusters(),
+		replicationclusterrole.DefaultReplicateFor,
+		labels.Everything(),
 	)
 	if err != nil {
 		return err
@@ -928,6 +947,26 @@ func (s *Server) installAPIExportController(ctx context.Context, config *rest.Co
 		kubeClusterClient,
 		s.KubeSharedInformerFactory.Rbac().V1().ClusterRoleBindings(),
 		s.KubeSharedInformerFactory.Rbac().V1().ClusterRoles(),
+		replicationclusterrole.DefaultReplicateFor,
+	)
+	if err != nil {
+		return err
+	}
+
+	roleReplication, err := replicationrole.NewController(
+		kubeClusterClient,
+		s.KubeSharedInformerFactory.Rbac().V1().Roles(),
+		s.KubeSharedInformerFactory.Rbac().V1().RoleBindings(),
+		replicationclusterrole.DefaultReplicateFor,
+	)
+	if err != nil {
+		return err
+	}
+
+	roleBindingReplication, err := replicationrolebinding.NewController(
+		kubeClusterClient,
+		s.KubeSharedInformerFactory.Rbac().V1().RoleBindings(),
+		replicationclusterrole.DefaultReplicateFor,
 	)
 	if err != nil {
 		return err
@@ -950,6 +989,39 @@ func (s *Server) installAPIExportController(ctx context.Context, config *rest.Co
 		go c.Start(goContext(hookContext), 2)
 		go clusterRoleReplication.Start(goContext(hookContext), 2)
 		go clusterRoleBindingReplication.Start(goContext(hookContext), 2)
+		go roleReplication.Start(goContext(hookContext), 2)
+		go roleBindingReplication.Start(goContext(hookContext), 2)
+
+		return nil
+	})
+}
+
+func (s *Server) installAPIResourceSchemaReferencesController(ctx context.Context, config *rest.Config, server *genericapiserver.GenericAPIServer) error {
+	config = rest.CopyConfig(config)
+	config = rest.AddUserAgent(config, apiresourceschemareferences.ControllerName)
+
+	kcpClusterClient, err := kcpclientset.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	c, err := apiresourceschemareferences.NewController(
+		kcpClusterClient,
+		s.KcpSharedInformerFactory.Apis().V1alpha1().APIResourceSchemas(),
+		s.KcpSharedInformerFactory.Apis().V1alpha1().APIExports(),
+	)
+	if err != nil {
+		return err
+	}
+
+	return server.AddPostStartHook(postStartHookName(apiresourceschemareferences.ControllerName), func(hookContext genericapiserver.PostStartHookContext) error {
+		logger := klog.FromContext(ctx).WithValues("postStartHook", postStartHookName(apiresourceschemareferences.ControllerName))
+		if err := s.waitForSync(hookContext.StopCh); err != nil {
+			logger.Error(err, "failed to finish post-start-hook")
+			return nil // don't klog.Fatal. This only happens when context is cancelled.
+		}
+
+		go c.Start(goContext(hookContext), 2)
 
 		return nil
 	})
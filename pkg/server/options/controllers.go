@@ -29,6 +29,7 @@ import (
 	"k8s.io/klog/v2"
 	kcmoptions "k8s.io/kubernetes/cmd/kube-controller-manager/app/options"
 
+	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apibinding"
 	"github.com/kcp-dev/kcp/pkg/reconciler/apis/apiresource"
 	"github.com/kcp-dev/kcp/pkg/reconciler/workload/heartbeat"
 )
@@ -37,11 +38,13 @@ type Controllers struct {
 	EnableAll           bool
 	IndividuallyEnabled []string
 	ApiResource         ApiResourceController
+	APIBinding          APIBindingController
 	SyncTargetHeartbeat SyncTargetHeartbeatController
 	SAController        kcmoptions.SAControllerOptions
 }
 
 type ApiResourceController = apiresource.Options
+type APIBindingController = apibinding.Options
 type SyncTargetHeartbeatController = heartbeat.Options
 
 var kcmDefaults *kcmoptions.KubeControllerManagerOptions
@@ -60,6 +63,7 @@ func NewControllers() *Controllers {
 		EnableAll: true,
 
 		ApiResource:         *apiresource.DefaultOptions(),
+		APIBinding:          *apibinding.DefaultOptions(),
 		SyncTargetHeartbeat: *heartbeat.DefaultOptions(),
 		SAController:        *kcmDefaults.SAController,
 	}
@@ -72,6 +76,7 @@ func (c *Controllers) AddFlags(fs *pflag.FlagSet) {
 	fs.MarkHidden("unsupported-run-individual-controllers") //nolint:errcheck
 
 	apiresource.BindOptions(&c.ApiResource, fs)
+	apibinding.BindOptions(&c.APIBinding, fs)
 	heartbeat.BindOptions(&c.SyncTargetHeartbeat, fs)
 
 	c.SAController.AddFlags(fs)
@@ -109,6 +114,9 @@ func (c *Controllers) Validate() []error {
 	if err := c.ApiResource.Validate(); err != nil {
 		errs = append(errs, err)
 	}
+	if err := c.APIBinding.Validate(); err != nil {
+		errs = append(errs, err)
+	}
 	if err := c.SyncTargetHeartbeat.Validate(); err != nil {
 		errs = append(errs, err)
 	}
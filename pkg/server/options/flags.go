@@ -175,6 +175,9 @@ var (
 		// KCP Controllers flags
 		"auto-publish-apis",                      // If true, the APIs imported from physical clusters will be published automatically as CRDs
 		"apiresource-controller-threads",         // Number of threads to use for the apiresource controller.
+		"apibinding-propagate-metadata-prefixes", // Prefixes of the label and annotation keys of APIResourceSchemas that are copied onto their bound CRDs.
+		"apibinding-removed-schema-policy",       // What happens to bound resources that are removed from their APIExport.
+		"apibinding-repair-drifted-subresources", // Repair the subresources of bound CRDs that drifted from their APIResourceSchema.
 		"run-controllers",                        // Run the controllers in-process
 		"run-virtual-workspaces",                 // Run the virtual workspaces apiservers in-process
 		"unsupported-run-individual-controllers", // Run individual controllers in-process. The controller names can change at any time.
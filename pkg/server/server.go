@@ -439,6 +439,9 @@ func (s *Server) Run(ctx context.Context) error {
 		if err := s.installAPIExportController(ctx, controllerConfig, delegationChainHead); err != nil {
 			return err
 		}
+		if err := s.installAPIResourceSchemaReferencesController(ctx, controllerConfig, delegationChainHead); err != nil {
+			return err
+		}
 	}
 
 	if s.Options.Controllers.EnableAll || enabled.Has("apiexportendpointslice") {
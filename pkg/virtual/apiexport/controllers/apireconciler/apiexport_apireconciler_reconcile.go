@@ -131,7 +131,7 @@ func (c *APIReconciler) reconcile(ctx context.Context, apiExport *apisv1alpha1.A
 			continue
 		}
 
-		exports, err := c.apiExportIndexer.ByIndex(indexers.APIExportByIdentity, pc.IdentityHash)
+		exports, err := indexers.APIExportsByIdentityHash(c.apiExportIndexer, pc.IdentityHash)
 		if err != nil {
 			return err
 		}
@@ -141,13 +141,11 @@ func (c *APIReconciler) reconcile(ctx context.Context, apiExport *apisv1alpha1.A
 		// The kcp server resource handlers will make sure the right structural schemas are applied. Here,
 		// we can just pick one. To make it deterministic, we sort the exports.
 		sort.Slice(exports, func(i, j int) bool {
-			a := exports[i].(*apisv1alpha1.APIExport)
-			b := exports[j].(*apisv1alpha1.APIExport)
+			a, b := exports[i], exports[j]
 			return a.Name < b.Name && logicalcluster.From(a).String() < logicalcluster.From(b).String()
 		})
 
-		for _, obj := range exports {
-			export := obj.(*apisv1alpha1.APIExport)
+		for _, export := range exports {
 			candidates, err := c.getSchemasFromAPIExport(ctx, export)
 			if err != nil {
 				return err